@@ -0,0 +1,60 @@
+package idle
+
+import (
+	"net"
+	"time"
+)
+
+// Conn은 읽기, 쓰기가 일어날 때마다 데드라인을 자동으로 뒤로 미뤄주는 net.Conn 래퍼
+// ch03의 TestDeadline처럼 Read 호출 전마다 SetDeadline을 직접 호출하지 않아도 됨
+// 읽기와 쓰기 타임아웃은 각각 따로 설정할 수 있으며, 0 이하의 값이면 해당 방향의 데드라인을 건드리지 않음
+type Conn struct {
+	net.Conn
+
+	ReadTimeout  time.Duration // 마지막 읽기 이후 허용할 최대 유휴 시간
+	WriteTimeout time.Duration // 쓰기 하나가 끝날 때까지 허용할 최대 시간
+}
+
+// 읽기, 쓰기 타임아웃이 같은 경우가 대부분이므로, 하나의 값으로 양쪽을 모두 설정
+func New(conn net.Conn, timeout time.Duration) *Conn {
+	return NewConn(conn, timeout, timeout)
+}
+
+// 읽기 타임아웃과 쓰기 타임아웃을 따로 지정해 래핑
+func NewConn(conn net.Conn, readTimeout, writeTimeout time.Duration) *Conn {
+	return &Conn{
+		Conn:         conn,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+}
+
+// Read 메서드는 호출될 때마다 읽기 데드라인을 ReadTimeout만큼 연장한 후 읽음
+// 원격 노드가 ReadTimeout 동안 아무것도 보내지 않으면, Timeout() 이 true인 net.Error를 반환
+func (c *Conn) Read(p []byte) (int, error) {
+	if c.ReadTimeout > 0 {
+		err := c.Conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return c.Conn.Read(p)
+}
+
+// Write 메서드는 호출될 때마다 쓰기 데드라인을 WriteTimeout만큼 연장한 후 씀
+// 상대방이 데이터를 읽지 않아 송신 버퍼가 가득 찬 경우, 무한정 블로킹되는 것을 방지
+func (c *Conn) Write(p []byte) (int, error) {
+	if c.WriteTimeout > 0 {
+		err := c.Conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return c.Conn.Write(p)
+}
+
+// 래핑된 원래의 연결 객체를 반환
+// *tls.Conn이나 *net.UnixConn 등 구체 타입의 메서드가 필요할 때 사용
+func (c *Conn) Unwrap() net.Conn { return c.Conn }
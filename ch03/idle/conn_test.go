@@ -0,0 +1,95 @@
+package idle
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnExtendsReadDeadline(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	timeout := 200 * time.Millisecond
+	errs := make(chan error, 1)
+	reads := make(chan int, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			errs <- err
+			return
+		}
+		c := NewConn(conn, timeout, timeout)
+		defer func() { _ = c.Close() }()
+
+		buf := make([]byte, 1)
+		n := 0
+		for {
+			_, err := c.Read(buf)
+			if err != nil {
+				reads <- n
+				errs <- err
+				return
+			}
+			n++
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// 타임아웃의 절반 간격으로 데이터를 보내면, 전체 시간이 타임아웃보다 길어도 연결이 유지되어야 함
+	begin := time.Now()
+	for i := 0; i < 5; i++ {
+		time.Sleep(timeout / 2)
+		_, err = conn.Write([]byte("1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 이제 아무것도 보내지 않으면, 서버의 Read는 타임아웃되어야 함
+	err = <-errs
+	nErr, ok := err.(net.Error)
+	if !ok || !nErr.Timeout() {
+		t.Fatalf("expected timeout error; actual: %v", err)
+	}
+	if n := <-reads; n != 5 {
+		t.Errorf("expected 5 reads; actual %d", n)
+	}
+	if elapsed := time.Since(begin); elapsed < 5*timeout/2+timeout {
+		t.Errorf("timed out too early: %s", elapsed)
+	}
+}
+
+func TestConnWithoutTimeout(t *testing.T) {
+	server, client := net.Pipe()
+	defer func() { _ = client.Close() }()
+
+	// 타임아웃이 0이면 데드라인을 설정하지 않으므로, 원래 연결과 동일하게 동작
+	c := New(server, 0)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = client.Write([]byte("ping"))
+		_ = client.Close()
+	}()
+
+	b, err := io.ReadAll(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "ping" {
+		t.Errorf("expected %q; actual %q", "ping", b)
+	}
+	if c.Unwrap() != server {
+		t.Error("Unwrap did not return the wrapped connection")
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"context"
+	"net"
+)
+
+// 수신한 데이터를 그대로 돌려주는 핸들러
+// 읽은 만큼만 그대로 쓰므로, unixpacket처럼 메시지 경계를 보존하는 네트워크에서도 메시지 단위로 에코잉함
+func Echo() Handler {
+	return HandlerFunc(func(_ context.Context, conn net.Conn) {
+		// 연결마다 버퍼를 한 번만 할당해 재사용
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+
+			_, err = conn.Write(buf[:n])
+			if err != nil {
+				return
+			}
+		}
+	})
+}
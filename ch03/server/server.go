@@ -0,0 +1,305 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/awoodbeck/gnp/ch03/idle"
+)
+
+// Shutdown이나 Close 메서드 호출 후 Serve 메서드가 반환하는 에러
+var ErrServerClosed = errors.New("server closed")
+
+// 수락된 연결 하나를 처리하는 인터페이스
+// ServeConn 메서드가 반환되면, 서버가 연결을 닫아주므로 핸들러에서 직접 닫지 않아도 됨
+// ctx는 서버가 종료(Shutdown, Close)될 때 취소되므로, 오래 실행되는 핸들러는 ctx.Done 채널을 확인해야 함
+type Handler interface {
+	ServeConn(ctx context.Context, conn net.Conn)
+}
+
+// 일반 함수를 Handler 인터페이스로 사용할 수 있게 해주는 어댑터 타입
+// http.HandlerFunc와 같은 역할
+type HandlerFunc func(ctx context.Context, conn net.Conn)
+
+func (f HandlerFunc) ServeConn(ctx context.Context, conn net.Conn) { f(ctx, conn) }
+
+// Server는 Listen, Accept, 연결별 고루틴 생성으로 이루어진 스트림 기반 서버의 공통 부분을 담당
+// tcp, unix, unixpacket 리스너나 tls.NewListener로 감싼 리스너 모두 사용 가능
+type Server struct {
+	Handler Handler // 연결을 처리할 핸들러
+
+	// 동시에 처리할 수 있는 최대 연결 수. 0 이하면 제한 없음
+	// 최대치에 도달하면 기존 연결이 닫힐 때까지 Accept를 호출하지 않으므로,
+	// 새로운 연결 요청은 운영체제의 백로그 큐에서 대기함
+	MaxConns int
+
+	// 0보다 크면, 연결을 idle.Conn으로 감싸 읽기, 쓰기마다 데드라인을 연장
+	IdleTimeout time.Duration
+
+	// Accept 메서드가 에러를 반환할 때마다 호출되는 훅. nil이면 무시
+	// 일시적인 에러는 잠시 대기한 후 다시 Accept를 시도하고, 그 외의 에러는 Serve 메서드가 반환함
+	OnAcceptError func(err error)
+
+	// 연결 처리 중 발생한 패닉 등을 기록할 로거. nil이면 log 패키지의 기본 로거를 사용
+	ErrorLog *log.Logger
+
+	mu         sync.Mutex
+	listeners  map[net.Listener]struct{}
+	conns      map[net.Conn]struct{}
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	inShutdown atomic.Bool
+}
+
+// 주어진 네트워크와 주소로 리스너를 생성한 후, Serve 메서드를 호출
+// 소켓 활성화로 상속받은 리스너나 추상 네임스페이스 주소가 필요하면,
+// ch07/sockets 패키지의 Listen 함수로 생성한 리스너를 Serve 메서드에 직접 넘김
+func (s *Server) ListenAndServe(network, addr string) error {
+	if s.shuttingDown() {
+		return ErrServerClosed
+	}
+
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return fmt.Errorf("binding to %s %s: %w", network, addr, err)
+	}
+
+	return s.Serve(l)
+}
+
+// 리스너로부터 연결을 수락하고, 각 연결을 별도의 고루틴에서 Handler에게 넘김
+// 항상 nil이 아닌 에러를 반환하며, Shutdown이나 Close 호출 후에는 ErrServerClosed를 반환
+func (s *Server) Serve(l net.Listener) error {
+	if s.Handler == nil {
+		return errors.New("handler is required")
+	}
+
+	if !s.trackListener(l, true) {
+		_ = l.Close()
+		return ErrServerClosed
+	}
+	defer s.trackListener(l, false)
+
+	// MaxConns 만큼의 버퍼를 가진 채널을 세마포어로 사용
+	var sem chan struct{}
+	if s.MaxConns > 0 {
+		sem = make(chan struct{}, s.MaxConns)
+	}
+
+	var delay time.Duration // 일시적인 Accept 에러 발생 시 대기 시간
+
+	for {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-s.baseContext().Done():
+				return ErrServerClosed
+			}
+		}
+
+		conn, err := l.Accept()
+		if err != nil {
+			if sem != nil {
+				<-sem
+			}
+			if s.shuttingDown() {
+				return ErrServerClosed
+			}
+			if s.OnAcceptError != nil {
+				s.OnAcceptError(err)
+			}
+			// net/http 서버와 같이, 일시적인 에러는 지수적으로 대기 시간을 늘리며 재시도
+			if tErr, ok := err.(interface{ Temporary() bool }); ok && tErr.Temporary() {
+				if delay == 0 {
+					delay = 5 * time.Millisecond
+				} else if delay *= 2; delay > time.Second {
+					delay = time.Second
+				}
+				s.logf("accept error: %v; retrying in %v", err, delay)
+				time.Sleep(delay)
+				continue
+			}
+
+			return err
+		}
+		delay = 0
+
+		if s.IdleTimeout > 0 {
+			conn = idle.New(conn, s.IdleTimeout)
+		}
+
+		if !s.trackConn(conn, true) {
+			_ = conn.Close()
+			if sem != nil {
+				<-sem
+			}
+			return ErrServerClosed
+		}
+
+		go func() {
+			defer func() {
+				// 핸들러 하나의 패닉이 서버 전체를 종료시키지 않도록 복구
+				if r := recover(); r != nil {
+					s.logf("panic serving %v: %v\n%s", conn.RemoteAddr(), r,
+						debug.Stack())
+				}
+				_ = conn.Close()
+				s.trackConn(conn, false)
+				if sem != nil {
+					<-sem
+				}
+			}()
+
+			s.Handler.ServeConn(s.baseContext(), conn)
+		}()
+	}
+}
+
+// 현재 처리 중인 연결의 수를 반환
+func (s *Server) ActiveConns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.conns)
+}
+
+// 서버를 우아하게 종료
+// 모든 리스너를 닫고 핸들러의 콘텍스트를 취소한 후, 처리 중인 연결이 모두 끝날 때까지 대기
+// ctx가 먼저 만료되면 남은 연결을 강제로 닫고 ctx.Err()를 반환
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.inShutdown.Store(true)
+
+	s.mu.Lock()
+	err := s.closeListenersLocked()
+	s.initLocked()
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		s.mu.Lock()
+		for c := range s.conns {
+			_ = c.Close()
+		}
+		s.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// 모든 리스너와 연결을 즉시 닫음
+// 핸들러가 반환될 때까지 기다리지 않음
+func (s *Server) Close() error {
+	s.inShutdown.Store(true)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.closeListenersLocked()
+	s.initLocked()
+	s.cancel()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+
+	return err
+}
+
+func (s *Server) shuttingDown() bool { return s.inShutdown.Load() }
+
+// 제로 값의 Server도 사용할 수 있도록 필요한 필드를 지연 초기화
+// s.mu를 잠근 상태에서 호출해야 함
+func (s *Server) initLocked() {
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+}
+
+func (s *Server) baseContext() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initLocked()
+
+	return s.ctx
+}
+
+func (s *Server) closeListenersLocked() error {
+	var err error
+	for l := range s.listeners {
+		if cErr := l.Close(); cErr != nil && err == nil {
+			err = cErr
+		}
+	}
+
+	return err
+}
+
+// 리스너를 추적 목록에 추가하거나 제거
+// 서버가 이미 종료 중이라면 추가하지 않고 false를 반환
+func (s *Server) trackListener(l net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initLocked()
+
+	if add {
+		if s.shuttingDown() {
+			return false
+		}
+		s.listeners[l] = struct{}{}
+	} else {
+		delete(s.listeners, l)
+	}
+
+	return true
+}
+
+// 연결을 추적 목록에 추가하거나 제거
+// Shutdown 메서드가 대기할 수 있도록 WaitGroup도 함께 관리
+func (s *Server) trackConn(c net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initLocked()
+
+	if add {
+		if s.shuttingDown() {
+			return false
+		}
+		s.conns[c] = struct{}{}
+		s.wg.Add(1)
+	} else {
+		delete(s.conns, c)
+		s.wg.Done()
+	}
+
+	return true
+}
+
+func (s *Server) logf(format string, v ...any) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 서버를 백그라운드에서 시작하고, 리스너 주소와 Serve 메서드의 반환 값을 받을 채널을 반환
+func startServer(t *testing.T, s *Server) (net.Addr, <-chan error) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- s.Serve(l) }()
+
+	return l.Addr(), errs
+}
+
+func TestServerEcho(t *testing.T) {
+	s := &Server{Handler: Echo()}
+	addr, errs := startServer(t, s)
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	msg := []byte("ping")
+	_, err = conn.Write(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, buf[:n]) {
+		t.Fatalf("expected reply %q; actual reply %q", msg, buf[:n])
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != ErrServerClosed {
+		t.Fatalf("expected ErrServerClosed; actual %v", err)
+	}
+
+	// Close 호출 후에는 기존 연결도 닫혀 있어야 함
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err = conn.Read(buf); err != io.EOF {
+		t.Fatalf("expected io.EOF; actual %v", err)
+	}
+}
+
+func TestServerMaxConns(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+
+	s := &Server{
+		MaxConns: 2,
+		Handler: HandlerFunc(func(_ context.Context, conn net.Conn) {
+			started <- struct{}{}
+			<-release
+		}),
+	}
+	addr, _ := startServer(t, s)
+	defer func() { _ = s.Close() }()
+
+	for i := 0; i < 3; i++ {
+		conn, err := net.Dial("tcp", addr.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = conn.Close() }()
+	}
+
+	// 최대 2개의 연결만 동시에 처리되어야 함
+	for i := 0; i < 2; i++ {
+		<-started
+	}
+	select {
+	case <-started:
+		t.Fatal("third connection handled while at MaxConns")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if n := s.ActiveConns(); n != 2 {
+		t.Fatalf("expected 2 active connections; actual %d", n)
+	}
+
+	// 처리 중인 연결이 끝나면, 대기 중이던 연결이 처리되어야 함
+	release <- struct{}{}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("third connection never handled")
+	}
+	close(release)
+}
+
+func TestServerShutdown(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		canceled bool
+	)
+	s := &Server{
+		Handler: HandlerFunc(func(ctx context.Context, conn net.Conn) {
+			// 서버가 종료를 시작하면 ctx가 취소되어야 함
+			<-ctx.Done()
+			mu.Lock()
+			canceled = true
+			mu.Unlock()
+		}),
+	}
+	addr, errs := startServer(t, s)
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	for s.ActiveConns() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != ErrServerClosed {
+		t.Fatalf("expected ErrServerClosed; actual %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !canceled {
+		t.Error("handler context was not canceled")
+	}
+	if n := s.ActiveConns(); n != 0 {
+		t.Errorf("expected 0 active connections; actual %d", n)
+	}
+
+	// 종료된 서버는 다시 시작할 수 없음
+	if err := s.ListenAndServe("tcp", "127.0.0.1:"); err != ErrServerClosed {
+		t.Errorf("expected ErrServerClosed; actual %v", err)
+	}
+}
+
+func TestServerShutdownTimeout(t *testing.T) {
+	// 콘텍스트를 무시하고 연결이 닫힐 때까지 읽기만 하는 핸들러
+	s := &Server{
+		Handler: HandlerFunc(func(_ context.Context, conn net.Conn) {
+			_, _ = io.Copy(io.Discard, conn)
+		}),
+	}
+	addr, _ := startServer(t, s)
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	for s.ActiveConns() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		100*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded; actual %v", err)
+	}
+
+	// 대기 시간이 지나면 남은 연결은 강제로 닫힘
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err = conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected io.EOF; actual %v", err)
+	}
+}
+
+func TestServerRecoversPanic(t *testing.T) {
+	logs := new(bytes.Buffer)
+	s := &Server{
+		ErrorLog: log.New(logs, "", 0),
+		Handler: HandlerFunc(func(_ context.Context, conn net.Conn) {
+			buf := make([]byte, 4)
+			n, _ := conn.Read(buf)
+			if string(buf[:n]) == "boom" {
+				panic("boom")
+			}
+			_, _ = conn.Write(buf[:n])
+		}),
+	}
+	addr, _ := startServer(t, s)
+	defer func() { _ = s.Close() }()
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = conn.Write([]byte("boom"))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err = conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected io.EOF; actual %v", err)
+	}
+	_ = conn.Close()
+
+	// 패닉 이후에도 서버는 계속 연결을 처리해야 함
+	conn, err = net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	_, _ = conn.Write([]byte("ping"))
+	buf := make([]byte, 4)
+	if _, err = io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(logs.String(), "panic serving") {
+		t.Errorf("panic was not logged: %q", logs.String())
+	}
+}
+
+type errListener struct {
+	net.Listener
+	err error
+}
+
+func (l errListener) Accept() (net.Conn, error) { return nil, l.err }
+
+func TestServerAcceptErrorHook(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+
+	var hooked error
+	acceptErr := errors.New("accept failed")
+	s := &Server{
+		Handler:       Echo(),
+		OnAcceptError: func(err error) { hooked = err },
+	}
+
+	// 일시적이지 않은 에러는 훅을 호출한 후 Serve 메서드가 그대로 반환
+	err = s.Serve(errListener{Listener: l, err: acceptErr})
+	if err != acceptErr {
+		t.Fatalf("expected %v; actual %v", acceptErr, err)
+	}
+	if hooked != acceptErr {
+		t.Fatalf("hook received %v", hooked)
+	}
+}
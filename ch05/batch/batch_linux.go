@@ -0,0 +1,23 @@
+package batch
+
+import (
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// 리눅스에서는 x/net 패키지를 통해 recvmmsg/sendmmsg 시스템 콜을 사용
+// 소켓의 주소 체계에 맞는 패킷 연결 객체를 선택해야 송신자 주소를 올바르게 해석함
+// x/net 패키지는 IP 주소만 해석하므로, unixgram 등 UDP가 아닌 소켓에는 Single을 사용
+func New(pc net.PacketConn) Conn {
+	addr, ok := pc.LocalAddr().(*net.UDPAddr)
+	switch {
+	case !ok:
+		return Single{PacketConn: pc}
+	case addr.IP.To4() != nil:
+		return ipv4.NewPacketConn(pc)
+	default:
+		return ipv6.NewPacketConn(pc)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package batch
+
+import "net"
+
+// 리눅스 이외의 운영체제에서는 데이터그램을 하나씩 읽고 쓰는 대체 구현을 사용
+func New(pc net.PacketConn) Conn {
+	return Single{PacketConn: pc}
+}
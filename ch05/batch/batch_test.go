@@ -0,0 +1,50 @@
+package batch
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+func TestSingle(t *testing.T) {
+	s, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+	sc := Single{PacketConn: s}
+
+	client, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	for _, msg := range []string{"one", "two"} {
+		if _, err = client.WriteTo([]byte(msg), s.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 대체 구현은 수신 버퍼에 여러 데이터그램이 있어도 하나씩만 읽어야 함
+	ms := NewMessages(Size, 1024)
+	_ = s.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := sc.ReadBatch(ms, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || string(ms[0].Buffers[0][:ms[0].N]) != "one" {
+		t.Fatalf("unexpected batch: %d messages, first %q", n,
+			ms[0].Buffers[0][:ms[0].N])
+	}
+
+	out := []ipv4.Message{
+		{Buffers: [][]byte{[]byte("a")}, Addr: client.LocalAddr()},
+		{Buffers: [][]byte{[]byte("b")}, Addr: client.LocalAddr()},
+	}
+	if n, err = sc.WriteBatch(out, 0); err != nil || n != 2 {
+		t.Fatalf("wrote %d messages: %v", n, err)
+	}
+}
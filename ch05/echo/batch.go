@@ -0,0 +1,29 @@
+package echo
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/awoodbeck/gnp/ch05/batch"
+)
+
+// echoServerUDP와 같지만, 최대 batch.Size 개의 데이터그램을 한 번에 읽고 한 번에 돌려보냄
+// 패킷이 몰려 들어오는 상황에서 시스템 콜 수가 줄어들어 초당 처리할 수 있는 패킷 수가 늘어남
+func batchEchoServerUDP(ctx context.Context, addr string) (net.Addr, error) {
+	s, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("binding to udp %s: %w", addr, err)
+	}
+
+	go func() {
+		go func() {
+			<-ctx.Done()
+			_ = s.Close()
+		}()
+
+		_ = batch.Echo(batch.New(s), 1024)
+	}()
+
+	return s.LocalAddr(), nil
+}
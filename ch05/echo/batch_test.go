@@ -0,0 +1,60 @@
+package echo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/awoodbeck/gnp/ch05/batch"
+)
+
+// 서버로 burst 개의 데이터그램을 연달아 보낸 후, 모든 응답을 받았는지 확인
+func testEchoBurst(t *testing.T, serverAddr net.Addr, burst int) {
+	t.Helper()
+
+	client, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	want := make(map[string]bool)
+	for i := 0; i < burst; i++ {
+		msg := fmt.Sprintf("%03d", i)
+		want[msg] = true
+		if _, err = client.WriteTo([]byte(msg), serverAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := make([]byte, 1024)
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(want) > 0 {
+		n, addr, err := client.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("%d replies missing: %v", len(want), err)
+		}
+		if addr.String() != serverAddr.String() {
+			t.Fatalf("received reply from %q instead of %q", addr, serverAddr)
+		}
+		if !want[string(buf[:n])] {
+			t.Fatalf("unexpected reply %q", buf[:n])
+		}
+		delete(want, string(buf[:n]))
+	}
+}
+
+func TestBatchEchoServerUDP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serverAddr, err := batchEchoServerUDP(ctx, "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 수신 버퍼에 쌓일 수 있을 만큼의 데이터그램을 한 번에 보냄
+	testEchoBurst(t, serverAddr, batch.Size/2)
+}
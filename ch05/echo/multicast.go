@@ -0,0 +1,303 @@
+package echo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// 멀티캐스트 연결의 옵션
+type multicastOptions struct {
+	// 그룹에 가입하거나 패킷을 내보낼 인터페이스. nil이면 운영체제가 선택
+	ifi *net.Interface
+	// IPv4의 TTL, IPv6의 hop limit. 0이면 운영체제 기본값(1)을 사용해 로컬 네트워크 밖으로 나가지 않음
+	ttl int
+	// 보낸 패킷을 같은 호스트에서 그룹에 가입한 소켓도 받을지 여부
+	loopback bool
+}
+
+// 멀티캐스트 그룹 주소를 알고 있는 net.PacketConn
+// IPv4, IPv6 중 하나에 해당하는 x/net 패킷 연결 객체를 이용해 소켓 옵션을 설정
+type multicastConn struct {
+	net.PacketConn
+	group *net.UDPAddr
+	p4    *ipv4.PacketConn
+	p6    *ipv6.PacketConn
+}
+
+// 그룹으로 데이터그램을 전송
+func (c *multicastConn) writeToGroup(p []byte) (int, error) {
+	return c.WriteTo(p, c.group)
+}
+
+// 그룹에 가입하는 멀티캐스트 리스너를 생성
+// network는 udp4 또는 udp6, group은 "239.0.0.250:9999"나 "[ff02::114]:9999" 같은 그룹 주소:포트
+// 같은 호스트의 여러 프로세스가 하나의 그룹을 수신할 수 있도록 주소 재사용 옵션을 설정함
+func listenMulticast(network, group string,
+	opts multicastOptions) (*multicastConn, error) {
+	gAddr, err := resolveGroup(network, group)
+	if err != nil {
+		return nil, err
+	}
+
+	// 그룹의 포트로 와일드카드 주소에 바인딩해야 그룹으로 전송된 패킷을 받을 수 있음
+	lc := net.ListenConfig{Control: reuseAddr}
+	pc, err := lc.ListenPacket(context.Background(), network,
+		net.JoinHostPort("", fmt.Sprint(gAddr.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("binding to %s %s: %w", network, group, err)
+	}
+
+	c, err := newMulticastConn(pc, gAddr, opts)
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+
+	// 인터페이스에서 그룹에 가입
+	join := &net.UDPAddr{IP: gAddr.IP}
+	if c.p4 != nil {
+		err = c.p4.JoinGroup(opts.ifi, join)
+	} else {
+		err = c.p6.JoinGroup(opts.ifi, join)
+	}
+	if err != nil {
+		_ = pc.Close()
+		return nil, fmt.Errorf("joining group %s: %w", gAddr.IP, err)
+	}
+
+	return c, nil
+}
+
+// 그룹에 가입하지 않고 그룹으로 전송만 하는 멀티캐스트 송신자를 생성
+// 임의의 포트에 바인딩하므로, 수신자는 이 주소로 유니캐스트 응답을 보낼 수 있음
+func dialMulticast(network, group string,
+	opts multicastOptions) (*multicastConn, error) {
+	gAddr, err := resolveGroup(network, group)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := net.ListenPacket(network, net.JoinHostPort("", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("binding to %s: %w", network, err)
+	}
+
+	c, err := newMulticastConn(pc, gAddr, opts)
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func resolveGroup(network, group string) (*net.UDPAddr, error) {
+	gAddr, err := net.ResolveUDPAddr(network, group)
+	if err != nil {
+		return nil, err
+	}
+	if !gAddr.IP.IsMulticast() {
+		return nil, fmt.Errorf("%s is not a multicast address", gAddr.IP)
+	}
+
+	return gAddr, nil
+}
+
+// TTL, 루프백, 송신 인터페이스 옵션을 설정
+func newMulticastConn(pc net.PacketConn, group *net.UDPAddr,
+	opts multicastOptions) (*multicastConn, error) {
+	c := &multicastConn{PacketConn: pc, group: group}
+
+	if group.IP.To4() != nil {
+		c.p4 = ipv4.NewPacketConn(pc)
+		if opts.ifi != nil {
+			if err := c.p4.SetMulticastInterface(opts.ifi); err != nil {
+				return nil, err
+			}
+		}
+		if opts.ttl > 0 {
+			if err := c.p4.SetMulticastTTL(opts.ttl); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.p4.SetMulticastLoopback(opts.loopback); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+
+	c.p6 = ipv6.NewPacketConn(pc)
+	if opts.ifi != nil {
+		if err := c.p6.SetMulticastInterface(opts.ifi); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ttl > 0 {
+		if err := c.p6.SetMulticastHopLimit(opts.ttl); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.p6.SetMulticastLoopback(opts.loopback); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// 인터페이스의 첫 번째 IPv4 주소에 대한 브로드캐스트 주소를 반환
+// 예) 192.168.1.10/24 -> 192.168.1.255
+// Go는 IPv4 UDP 소켓에 SO_BROADCAST 옵션을 기본으로 설정하므로, 이 주소로 바로 WriteTo 할 수 있음
+func broadcastAddr(ifi *net.Interface, port int) (*net.UDPAddr, error) {
+	if ifi.Flags&net.FlagBroadcast == 0 {
+		return nil, fmt.Errorf("%s does not support broadcast", ifi.Name)
+	}
+
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip4 := ipNet.IP.To4()
+		if ip4 == nil {
+			continue
+		}
+
+		mask := ipNet.Mask
+		if len(mask) == net.IPv6len {
+			mask = mask[12:]
+		}
+		bcast := make(net.IP, net.IPv4len)
+		for i := range ip4 {
+			bcast[i] = ip4[i] | ^mask[i]
+		}
+
+		return &net.UDPAddr{IP: bcast, Port: port}, nil
+	}
+
+	return nil, fmt.Errorf("%s has no IPv4 address", ifi.Name)
+}
+
+// 서비스 탐색 프로토콜의 메시지
+// 탐색자가 그룹으로 discoverMsg를 보내면, 각 피어는 탐색자에게 유니캐스트로 "PEER <이름>"을 응답
+const (
+	discoverMsg = "DISCOVER"
+	peerPrefix  = "PEER "
+)
+
+// 탐색으로 찾은 피어
+type peer struct {
+	name string
+	addr net.Addr
+}
+
+// 그룹에 가입해 탐색 요청에 자신의 이름으로 응답하는 피어를 시작
+// echoServerUDP와 마찬가지로 콘텍스트가 취소되면 종료되며, 피어의 주소를 반환
+func discoveryPeer(ctx context.Context, network, group, name string,
+	opts multicastOptions) (net.Addr, error) {
+	c, err := listenMulticast(network, group, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		go func() {
+			<-ctx.Done()
+			_ = c.Close()
+		}()
+
+		reply := []byte(peerPrefix + name)
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := c.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if string(buf[:n]) != discoverMsg {
+				continue
+			}
+			// 탐색자에게 직접 응답
+			_, err = c.WriteTo(reply, addr)
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return c.LocalAddr(), nil
+}
+
+// 그룹으로 탐색 요청을 보내고, 콘텍스트가 만료될 때까지 받은 피어의 응답을 수집
+// UDP 특성상 요청이 유실될 수 있으므로, interval마다 요청을 다시 보냄
+// 같은 이름의 피어는 한 번만 반환
+func discoverPeers(ctx context.Context, network, group string,
+	interval time.Duration, opts multicastOptions) ([]peer, error) {
+	c, err := dialMulticast(network, group, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 수신 중 에러가 발생해도 요청을 보내는 고루틴이 종료되도록 별도의 콘텍스트를 사용
+	sendCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			_, _ = c.writeToGroup([]byte(discoverMsg))
+			select {
+			case <-sendCtx.Done():
+				// 콘텍스트가 만료되면 연결을 닫아, 아래에서 블로킹된 ReadFrom을 반환시킴
+				_ = c.Close()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var (
+		peers []peer
+		seen  = make(map[string]struct{})
+		buf   = make([]byte, 1024)
+	)
+	for {
+		n, addr, err := c.ReadFrom(buf)
+		if err != nil {
+			cancel()
+			wg.Wait()
+			if ctx.Err() != nil && errors.Is(err, net.ErrClosed) {
+				return peers, nil
+			}
+			return peers, err
+		}
+
+		msg := string(buf[:n])
+		if !strings.HasPrefix(msg, peerPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(msg, peerPrefix)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		peers = append(peers, peer{name: name, addr: addr})
+	}
+}
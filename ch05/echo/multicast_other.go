@@ -0,0 +1,9 @@
+//go:build !darwin && !linux
+
+package echo
+
+import "syscall"
+
+// 그 외의 운영체제에서는 주소 재사용 옵션을 설정하지 않음
+// 따라서 한 호스트에서 하나의 소켓만 그룹 포트에 바인딩할 수 있음
+func reuseAddr(_, _ string, _ syscall.RawConn) error { return nil }
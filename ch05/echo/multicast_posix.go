@@ -0,0 +1,29 @@
+//go:build darwin || linux
+
+package echo
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// 같은 호스트의 여러 소켓이 하나의 멀티캐스트 그룹 포트에 바인딩할 수 있도록 함
+// 리눅스는 SO_REUSEADDR, macOS는 SO_REUSEPORT가 필요하므로 둘 다 설정
+func reuseAddr(_, _ string, c syscall.RawConn) error {
+	var opErr error
+	err := c.Control(func(fd uintptr) {
+		opErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET,
+			unix.SO_REUSEADDR, 1)
+		if opErr != nil {
+			return
+		}
+		opErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET,
+			unix.SO_REUSEPORT, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	return opErr
+}
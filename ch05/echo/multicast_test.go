@@ -0,0 +1,163 @@
+package echo
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sort"
+	"testing"
+	"time"
+)
+
+// 주어진 기능(멀티캐스트, 브로드캐스트)을 지원하고 활성화된 첫 번째 인터페이스를 반환
+// 테스트 환경에 그런 인터페이스가 없으면 테스트를 건너뜀
+func upInterface(t *testing.T, flag net.Flags) *net.Interface {
+	t.Helper()
+
+	ifis, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ifi := range ifis {
+		if ifi.Flags&net.FlagUp == 0 || ifi.Flags&flag == 0 {
+			continue
+		}
+		// 브로드캐스트에는 IPv4 주소가 필요함
+		if flag == net.FlagBroadcast {
+			if _, err := broadcastAddr(&ifi, 0); err != nil {
+				continue
+			}
+		}
+
+		return &ifi
+	}
+	t.Skipf("no interface with %s support", flag)
+
+	return nil
+}
+
+func TestMulticastGroup(t *testing.T) {
+	ifi := upInterface(t, net.FlagMulticast)
+	opts := multicastOptions{ifi: ifi, loopback: true}
+
+	for _, c := range []struct{ network, group string }{
+		{"udp4", "239.0.0.250:42424"},
+		// IPv6 링크 로컬 그룹은 인터페이스를 지정해야 함
+		{"udp6", "[ff02::114]:42424"},
+	} {
+		t.Run(c.network, func(t *testing.T) {
+			// 같은 호스트의 두 리스너가 같은 그룹에 가입
+			var listeners []*multicastConn
+			for i := 0; i < 2; i++ {
+				l, err := listenMulticast(c.network, c.group, opts)
+				if err != nil {
+					t.Skipf("joining multicast group: %v", err)
+				}
+				defer func() { _ = l.Close() }()
+				listeners = append(listeners, l)
+			}
+
+			sender, err := dialMulticast(c.network, c.group, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = sender.Close() }()
+
+			msg := []byte("ping")
+			if _, err = sender.writeToGroup(msg); err != nil {
+				t.Skipf("sending to multicast group: %v", err)
+			}
+
+			// 그룹으로 보낸 하나의 메시지를 두 리스너 모두 받아야 함
+			buf := make([]byte, 1024)
+			for i, l := range listeners {
+				_ = l.SetReadDeadline(time.Now().Add(time.Second))
+				n, _, err := l.ReadFrom(buf)
+				if err != nil {
+					t.Fatalf("listener %d: %v", i, err)
+				}
+				if !bytes.Equal(msg, buf[:n]) {
+					t.Errorf("listener %d: expected %q; actual %q", i, msg,
+						buf[:n])
+				}
+			}
+		})
+	}
+}
+
+func TestMulticastRejectsUnicastGroup(t *testing.T) {
+	_, err := listenMulticast("udp4", "127.0.0.1:42424", multicastOptions{})
+	if err == nil {
+		t.Fatal("expected an error for a unicast group address")
+	}
+}
+
+func TestDiscoverPeers(t *testing.T) {
+	ifi := upInterface(t, net.FlagMulticast)
+	opts := multicastOptions{ifi: ifi, loopback: true}
+	group := "239.0.0.251:42425"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, name := range []string{"alpha", "beta"} {
+		if _, err := discoveryPeer(ctx, "udp4", group, name, opts); err != nil {
+			t.Skipf("joining multicast group: %v", err)
+		}
+	}
+
+	dctx, dcancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer dcancel()
+	peers, err := discoverPeers(dctx, "udp4", group, 100*time.Millisecond, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, p := range peers {
+		names = append(names, p.name)
+		t.Logf("discovered %s at %s", p.name, p.addr)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "alpha" || names[1] != "beta" {
+		t.Fatalf("expected peers alpha and beta; actual %v", names)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	ifi := upInterface(t, net.FlagBroadcast)
+
+	listener, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	bAddr, err := broadcastAddr(ifi,
+		listener.LocalAddr().(*net.UDPAddr).Port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("broadcasting to %s via %s", bAddr, ifi.Name)
+
+	sender, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sender.Close() }()
+
+	msg := []byte("ping")
+	if _, err = sender.WriteTo(msg, bAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, buf[:n]) {
+		t.Errorf("expected %q; actual %q", msg, buf[:n])
+	}
+}
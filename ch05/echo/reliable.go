@@ -0,0 +1,104 @@
+package echo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/awoodbeck/gnp/ch05/reliable"
+)
+
+// 클라이언트 하나에 대해 전송을 기다릴 수 있는 최대 응답 수
+// 이를 넘으면 응답을 버리며, 클라이언트가 같은 메시지를 다시 보내야 함
+const replyQueueSize = 16
+
+// echoServerUDP와 동일하지만, reliable 패키지를 이용해 유실된 데이터그램을 재전송하는 에코 서버
+// 클라이언트도 reliable.Conn을 사용해야 ACK와 재전송이 동작함
+//
+// WriteTo 메서드는 ACK를 받을 때까지 블로킹되므로, 응답은 클라이언트마다 별도의 고루틴에서 순서대로 보냄
+// ACK를 보내지 않는 클라이언트가 있어도 다른 클라이언트의 응답은 지연되지 않으며,
+// 해당 클라이언트에 대한 전송만 ErrNoAck로 실패함
+func reliableEchoServerUDP(ctx context.Context, addr string) (net.Addr, error) {
+	s, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("binding to udp %s: %w", addr, err)
+	}
+	// 기존 UDP 연결 객체를 감싸 시퀀스 번호, ACK, 재전송을 처리하도록 함
+	rs := reliable.New(s)
+
+	go func() {
+		go func() {
+			<-ctx.Done()
+			_ = rs.Close()
+		}()
+
+		r := &replier{conn: rs, queues: make(map[string]chan []byte)}
+		buf := make([]byte, reliable.MaxPayloadSize)
+		for {
+			// 중복이 제거된 메시지만 반환됨
+			n, clientAddr, err := rs.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			r.reply(clientAddr, append([]byte(nil), buf[:n]...))
+		}
+	}()
+
+	return rs.LocalAddr(), nil
+}
+
+// replier는 클라이언트별 큐와 고루틴으로 응답을 보냄
+// 큐가 빈 클라이언트의 고루틴은 종료되므로, 고루틴 수는 응답을 기다리는 클라이언트 수를 넘지 않음
+type replier struct {
+	conn *reliable.Conn
+
+	mu     sync.Mutex
+	queues map[string]chan []byte // 클라이언트 주소별 응답 큐
+}
+
+func (r *replier) reply(addr net.Addr, msg []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q, ok := r.queues[addr.String()]
+	if !ok {
+		q = make(chan []byte, replyQueueSize)
+		r.queues[addr.String()] = q
+		go r.send(addr, q)
+	}
+
+	select {
+	case q <- msg:
+	default:
+		log.Printf("dropping reply to %s: queue full", addr)
+	}
+}
+
+// 큐에 쌓인 응답을 보낸 순서대로 전송하고, 큐가 비면 종료
+func (r *replier) send(addr net.Addr, q chan []byte) {
+	for {
+		r.mu.Lock()
+		var msg []byte
+		select {
+		case msg = <-q:
+		default:
+			delete(r.queues, addr.String())
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+
+		// 클라이언트가 ACK를 보낼 때까지 블로킹되며, 필요하면 재전송함
+		_, err := r.conn.WriteTo(msg, addr)
+		switch {
+		case errors.Is(err, reliable.ErrClosed), errors.Is(err, net.ErrClosed):
+			return
+		case err != nil:
+			// 응답하지 않는 클라이언트 때문에 서버 전체를 종료하지 않음
+			log.Printf("replying to %s: %v", addr, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package echo
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/awoodbeck/gnp/ch05/reliable"
+)
+
+func TestReliableEchoServerUDP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	serverAddr, err := reliableEchoServerUDP(ctx, "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	c, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 클라이언트 역시 reliable.Conn으로 감싸야 서버의 응답에 ACK를 보낼 수 있음
+	client := reliable.New(c)
+	defer func() { _ = client.Close() }()
+
+	buf := make([]byte, reliable.MaxPayloadSize)
+	for _, msg := range [][]byte{[]byte("ping"), []byte("pong")} {
+		_, err = client.WriteTo(msg, serverAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n, addr, err := client.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr.String() != serverAddr.String() {
+			t.Fatalf("received reply from %q instead of %q", addr, serverAddr)
+		}
+		if !bytes.Equal(msg, buf[:n]) {
+			t.Errorf("expected reply %q; actual reply %q", msg, buf[:n])
+		}
+	}
+}
+
+func TestReliableEchoServerUDPSilentPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	serverAddr, err := reliableEchoServerUDP(ctx, "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	newClient := func() *reliable.Conn {
+		c, err := net.ListenPacket("udp", "127.0.0.1:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reliable.New(c)
+	}
+
+	// 메시지를 보낸 직후 연결을 닫아, 서버의 응답에 ACK를 보내지 않는 클라이언트
+	silent := newClient()
+	if _, err = silent.WriteTo([]byte("silent"), serverAddr); err != nil {
+		t.Fatal(err)
+	}
+	_ = silent.Close()
+
+	client := newClient()
+	defer func() { _ = client.Close() }()
+
+	echo := func(msg string, timeout time.Duration) {
+		t.Helper()
+
+		begin := time.Now()
+		if _, err := client.WriteTo([]byte(msg), serverAddr); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, reliable.MaxPayloadSize)
+		n, _, err := client.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := string(buf[:n]); actual != msg {
+			t.Fatalf("expected reply %q; actual reply %q", msg, actual)
+		}
+		if elapsed := time.Since(begin); elapsed > timeout {
+			t.Fatalf("reply to %q took %s", msg, elapsed)
+		}
+	}
+
+	// 서버가 응답하지 않는 클라이언트에게 재전송하는 동안에도 다른 클라이언트는 바로 응답을 받아야 함
+	echo("ping", time.Second)
+
+	// 응답하지 않는 클라이언트에 대한 전송이 ErrNoAck로 끝난 후에도 서버는 계속 동작해야 함
+	time.Sleep(3 * time.Second)
+	echo("pong", time.Second)
+}
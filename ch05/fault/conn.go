@@ -0,0 +1,50 @@
+package fault
+
+import "net"
+
+// PacketConn은 WriteTo 메서드로 보내는 데이터그램에 장애를 주입하는 net.PacketConn 래퍼
+// 받는 쪽의 장애를 흉내 내려면, 상대방의 연결 객체를 감싸면 됨
+type PacketConn struct {
+	net.PacketConn
+	*injector
+}
+
+func NewPacketConn(pc net.PacketConn, f Faults) *PacketConn {
+	return &PacketConn{PacketConn: pc, injector: newInjector(f)}
+}
+
+func (c *PacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	return c.apply(p, func(b []byte) error {
+		_, err := c.PacketConn.WriteTo(b, addr)
+		return err
+	})
+}
+
+func (c *PacketConn) Close() error {
+	c.close()
+	return c.PacketConn.Close()
+}
+
+// Conn은 Write 메서드로 보내는 데이터에 장애를 주입하는 net.Conn 래퍼
+// net.Dial("udp", ...)로 생성한 연결처럼 쓰기 하나가 데이터그램 하나인 경우에 의미가 있음
+// TCP 연결에 사용하면, 유실이나 순서 뒤바뀜이 바이트 스트림의 일부가 사라지거나 섞이는 것으로 나타남
+type Conn struct {
+	net.Conn
+	*injector
+}
+
+func NewConn(c net.Conn, f Faults) *Conn {
+	return &Conn{Conn: c, injector: newInjector(f)}
+}
+
+func (c *Conn) Write(p []byte) (int, error) {
+	return c.apply(p, func(b []byte) error {
+		_, err := c.Conn.Write(b)
+		return err
+	})
+}
+
+func (c *Conn) Close() error {
+	c.close()
+	return c.Conn.Close()
+}
@@ -0,0 +1,176 @@
+package fault
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Faults는 쓰기마다 주입할 장애의 종류와 확률을 나타냄
+// 같은 Seed를 사용하면 항상 같은 순서로 장애가 발생하므로, 테스트를 결정적으로 재현할 수 있음
+type Faults struct {
+	Seed int64 // 난수 생성기의 시드
+
+	Loss      float64 // 패킷을 버릴 확률 (0 ~ 1)
+	Duplicate float64 // 패킷을 두 번 보낼 확률
+	Reorder   float64 // 패킷을 잡아두었다가 다음 패킷 뒤에 보낼 확률
+	Corrupt   float64 // 패킷의 임의의 비트 하나를 뒤집을 확률
+
+	Latency time.Duration // 모든 패킷의 전송을 지연시킬 시간
+}
+
+// 지금까지 주입된 장애의 횟수
+type Stats struct {
+	Writes     int // Write, WriteTo 메서드 호출 횟수
+	Dropped    int
+	Duplicated int
+	Reordered  int
+	Corrupted  int
+}
+
+// injector는 PacketConn과 Conn이 공유하는 장애 주입 로직
+// 실제 쓰기는 write 함수에 위임
+type injector struct {
+	f Faults
+
+	mu    sync.Mutex
+	rnd   *rand.Rand
+	held  *packet // 순서를 뒤바꾸기 위해 잡아둔 패킷
+	stats Stats
+
+	queue     chan delayed // Latency가 설정된 경우 지연 전송할 패킷
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// 전송할 패킷과, 그 패킷을 보낼 함수
+// PacketConn에서는 write 함수가 목적지 주소를 가지고 있으므로, 잡아둔 패킷도 자신의 목적지로 전송됨
+type packet struct {
+	b     []byte
+	write func([]byte) error
+}
+
+type delayed struct {
+	packet
+	due time.Time
+}
+
+func newInjector(f Faults) *injector {
+	i := &injector{
+		f:      f,
+		rnd:    rand.New(rand.NewSource(f.Seed)),
+		closed: make(chan struct{}),
+	}
+	if f.Latency > 0 {
+		i.queue = make(chan delayed, 1024)
+		go i.delayLoop()
+	}
+
+	return i
+}
+
+// 패킷 하나에 장애를 주입한 후 write 함수로 전송
+// 유실된 패킷도 호출자에게는 정상적으로 쓴 것처럼 len(p)를 반환함
+func (i *injector) apply(p []byte, write func([]byte) error) (int, error) {
+	i.mu.Lock()
+	i.stats.Writes++
+
+	if i.roll(i.f.Loss) {
+		i.stats.Dropped++
+		i.mu.Unlock()
+		return len(p), nil
+	}
+
+	// 호출자의 버퍼를 수정하지 않도록 복사본을 사용
+	b := append([]byte(nil), p...)
+	if len(b) > 0 && i.roll(i.f.Corrupt) {
+		i.stats.Corrupted++
+		b[i.rnd.Intn(len(b))] ^= 1 << uint(i.rnd.Intn(8))
+	}
+
+	out := []packet{{b: b, write: write}}
+	if i.roll(i.f.Duplicate) {
+		i.stats.Duplicated++
+		out = append(out, out[0])
+	}
+
+	if i.held == nil && i.roll(i.f.Reorder) {
+		// 다음 패킷을 보낸 뒤에 보내도록 잡아둠
+		i.stats.Reordered++
+		i.held, out = &out[len(out)-1], out[:len(out)-1]
+	} else if i.held != nil {
+		out = append(out, *i.held)
+		i.held = nil
+	}
+	i.mu.Unlock()
+
+	for _, pkt := range out {
+		if err := i.send(pkt); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// 확률 p로 true를 반환. i.mu를 잠근 상태에서 호출해야 함
+func (i *injector) roll(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+
+	return i.rnd.Float64() < p
+}
+
+func (i *injector) send(pkt packet) error {
+	if i.queue == nil {
+		return pkt.write(pkt.b)
+	}
+
+	select {
+	case i.queue <- delayed{packet: pkt, due: time.Now().Add(i.f.Latency)}:
+		return nil
+	case <-i.closed:
+		return nil
+	}
+}
+
+// 지연된 패킷을 들어온 순서대로 전송
+func (i *injector) delayLoop() {
+	for {
+		select {
+		case d := <-i.queue:
+			if wait := time.Until(d.due); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-i.closed:
+					return
+				}
+			}
+			_ = d.write(d.b)
+		case <-i.closed:
+			return
+		}
+	}
+}
+
+func (i *injector) Stats() Stats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.stats
+}
+
+// 잡아둔 패킷이 있다면 전송한 후 지연 전송을 중단
+// 마지막 쓰기에서 잡아둔 패킷은 다음 쓰기가 없으면 보낼 기회가 없으므로, 유실되지 않도록 여기서 보냄
+func (i *injector) close() {
+	i.mu.Lock()
+	held := i.held
+	i.held = nil
+	i.mu.Unlock()
+	if held != nil {
+		_ = held.write(held.b)
+	}
+
+	i.closeOnce.Do(func() { close(i.closed) })
+}
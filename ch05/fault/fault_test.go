@@ -0,0 +1,206 @@
+package fault
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// 서버와 클라이언트 UDP 연결을 만들고, 클라이언트 연결을 장애 주입 래퍼로 감쌈
+func udpPair(t *testing.T, f Faults) (net.PacketConn, *PacketConn) {
+	t.Helper()
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	client, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc := NewPacketConn(client, f)
+	t.Cleanup(func() { _ = fc.Close() })
+
+	return server, fc
+}
+
+// 데드라인이 지날 때까지 받은 모든 데이터그램을 반환
+func readAll(t *testing.T, pc net.PacketConn, wait time.Duration) [][]byte {
+	t.Helper()
+
+	var msgs [][]byte
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(wait))
+	for {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			if nErr, ok := err.(net.Error); ok && nErr.Timeout() {
+				return msgs
+			}
+			t.Fatal(err)
+		}
+		msgs = append(msgs, append([]byte(nil), buf[:n]...))
+	}
+}
+
+func send(t *testing.T, c *PacketConn, to net.Addr, count int) {
+	t.Helper()
+
+	for i := 0; i < count; i++ {
+		n, err := c.WriteTo([]byte(fmt.Sprintf("%02d", i)), to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 유실된 경우에도 정상적으로 쓴 것처럼 보여야 함
+		if n != 2 {
+			t.Fatalf("expected 2 bytes written; actual %d", n)
+		}
+	}
+}
+
+func TestLossIsDeterministic(t *testing.T) {
+	var results []Stats
+	for i := 0; i < 2; i++ {
+		server, client := udpPair(t, Faults{Seed: 42, Loss: 0.5})
+		send(t, client, server.LocalAddr(), 20)
+
+		msgs := readAll(t, server, 200*time.Millisecond)
+		stats := client.Stats()
+		if len(msgs) != stats.Writes-stats.Dropped {
+			t.Fatalf("received %d messages; expected %d", len(msgs),
+				stats.Writes-stats.Dropped)
+		}
+		results = append(results, stats)
+	}
+
+	// 같은 시드라면 같은 패킷이 유실되어야 함
+	if results[0] != results[1] {
+		t.Fatalf("same seed produced different results: %+v vs %+v",
+			results[0], results[1])
+	}
+	if results[0].Dropped == 0 || results[0].Dropped == 20 {
+		t.Fatalf("unexpected number of dropped packets: %d", results[0].Dropped)
+	}
+}
+
+func TestDuplicateAndReorder(t *testing.T) {
+	server, client := udpPair(t, Faults{Duplicate: 1})
+	send(t, client, server.LocalAddr(), 2)
+	msgs := readAll(t, server, 200*time.Millisecond)
+	if expected := "00000101"; string(bytes.Join(msgs, nil)) != expected {
+		t.Fatalf("expected %q; actual %q", expected, bytes.Join(msgs, nil))
+	}
+
+	// 첫 번째 패킷은 잡아두었다가 두 번째 패킷 다음에 보냄
+	server, client = udpPair(t, Faults{Reorder: 1})
+	send(t, client, server.LocalAddr(), 2)
+	msgs = readAll(t, server, 200*time.Millisecond)
+	if expected := "0100"; string(bytes.Join(msgs, nil)) != expected {
+		t.Fatalf("expected %q; actual %q", expected, bytes.Join(msgs, nil))
+	}
+}
+
+func TestReorderKeepsDestination(t *testing.T) {
+	server, client := udpPair(t, Faults{Reorder: 1})
+	other, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = other.Close() }()
+
+	// 잡아둔 첫 번째 패킷은 두 번째 쓰기의 목적지가 아닌, 자신의 목적지로 전송되어야 함
+	send(t, client, server.LocalAddr(), 1)
+	if _, err = client.WriteTo([]byte("other"), other.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := readAll(t, server, 200*time.Millisecond); len(msgs) != 1 ||
+		string(msgs[0]) != "00" {
+		t.Fatalf("expected %q; actual %q", "00", msgs)
+	}
+	if msgs := readAll(t, other, 200*time.Millisecond); len(msgs) != 1 ||
+		string(msgs[0]) != "other" {
+		t.Fatalf("expected %q; actual %q", "other", msgs)
+	}
+
+	// 마지막 쓰기에서 잡아둔 패킷은 연결을 닫을 때 전송되어야 함
+	send(t, client, server.LocalAddr(), 1)
+	_ = client.Close()
+	if msgs := readAll(t, server, 200*time.Millisecond); len(msgs) != 1 ||
+		string(msgs[0]) != "00" {
+		t.Fatalf("expected held packet on close; actual %q", msgs)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	server, client := udpPair(t, Faults{Corrupt: 1})
+	msg := []byte("ping")
+	_, err := client.WriteTo(msg, server.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := readAll(t, server, 200*time.Millisecond)
+	if len(msgs) != 1 || bytes.Equal(msgs[0], msg) {
+		t.Fatalf("expected one corrupted message; actual %q", msgs)
+	}
+	// 호출자의 버퍼는 수정되지 않아야 함
+	if string(msg) != "ping" {
+		t.Fatalf("caller's buffer was modified: %q", msg)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	server, client := udpPair(t, Faults{Latency: 100 * time.Millisecond})
+
+	begin := time.Now()
+	send(t, client, server.LocalAddr(), 3)
+	// 쓰기는 블로킹되지 않아야 함
+	if elapsed := time.Since(begin); elapsed > 50*time.Millisecond {
+		t.Fatalf("WriteTo blocked for %s", elapsed)
+	}
+
+	buf := make([]byte, 1024)
+	_ = server.SetReadDeadline(time.Now().Add(time.Second))
+	for i := 0; i < 3; i++ {
+		n, _, err := server.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 지연되더라도 보낸 순서는 유지되어야 함
+		if expected := fmt.Sprintf("%02d", i); string(buf[:n]) != expected {
+			t.Fatalf("expected %q; actual %q", expected, buf[:n])
+		}
+	}
+	if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+		t.Fatalf("packets arrived after %s", elapsed)
+	}
+}
+
+func TestConn(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+
+	c, err := net.Dial("udp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewConn(c, Faults{Loss: 1})
+	defer func() { _ = client.Close() }()
+
+	if _, err = client.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := readAll(t, server, 100*time.Millisecond); len(msgs) != 0 {
+		t.Fatalf("expected all messages dropped; received %q", msgs)
+	}
+	if s := client.Stats(); s.Writes != 1 || s.Dropped != 1 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+}
@@ -0,0 +1,127 @@
+package pmtu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	ipv4HeaderSize = 20
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+
+	// IPv4 UDP 데이터그램 페이로드의 최대 크기 (65,535 - 20 - 8)
+	MaxUDPPayload = 65507
+)
+
+// 운영체제가 경로 MTU 관련 소켓 옵션을 지원하지 않을 때 반환
+var ErrUnsupported = errors.New("path MTU discovery not supported on this platform")
+
+// MTU로부터 단편화 없이 보낼 수 있는 UDP 페이로드의 최대 크기를 계산
+// 예) 이더넷 MTU 1500 -> IPv4: 1472 바이트, IPv6: 1452 바이트
+func MaxPayload(mtu int, ipv6 bool) int {
+	if ipv6 {
+		return mtu - ipv6HeaderSize - udpHeaderSize
+	}
+
+	return mtu - ipv4HeaderSize - udpHeaderSize
+}
+
+// Prober는 연결된 UDP 소켓을 이용해 실제로 상대방까지 전달되는 최대 페이로드 크기를 찾음
+// 상대방은 받은 데이터그램을 그대로 돌려보내야 함 (ch05의 echoServerUDP 등)
+// 중간 라우터가 ICMP 메시지 없이 큰 패킷을 버리는 경우(PMTU 블랙홀)에도 동작하도록
+// 응답이 없는 크기는 전달되지 않는 것으로 간주함
+type Prober struct {
+	Min     int           // 항상 전달된다고 가정하는 크기. 0이면 512
+	Max     int           // 탐색할 최대 크기. 0이면 MaxUDPPayload
+	Timeout time.Duration // 응답을 기다릴 시간. 0이면 500ms
+	Retries int           // 크기마다 시도할 횟수. 0이면 3
+}
+
+// 이진 탐색으로 상대방까지 왕복 가능한 가장 큰 페이로드 크기를 반환
+// conn에 SetDontFragment를 먼저 호출해 두어야, 단편화로 인해 큰 데이터그램이 전달되는 것처럼 보이지 않음
+func (p Prober) Probe(conn *net.UDPConn) (int, error) {
+	lo, hi := p.Min, p.Max
+	if lo <= 0 {
+		lo = 512
+	}
+	if hi <= 0 {
+		hi = MaxUDPPayload
+	}
+	if lo > hi {
+		return 0, fmt.Errorf("invalid probe range %d-%d", lo, hi)
+	}
+
+	ok, err := p.try(conn, lo)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("no reply for %d byte probe", lo)
+	}
+
+	// lo는 항상 전달되는 크기, hi+1은 전달되지 않는 크기가 되도록 범위를 좁혀 나감
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		ok, err := p.try(conn, mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	return lo, nil
+}
+
+// size 바이트의 데이터그램을 보내고, 같은 데이터그램이 돌아오는지 확인
+func (p Prober) try(conn *net.UDPConn, size int) (bool, error) {
+	timeout, retries := p.Timeout, p.Retries
+	if timeout <= 0 {
+		timeout = 500 * time.Millisecond
+	}
+	if retries <= 0 {
+		retries = 3
+	}
+
+	probe := bytes.Repeat([]byte{byte(size)}, size)
+	buf := make([]byte, size+1)
+
+	for i := 0; i < retries; i++ {
+		_, err := conn.Write(probe)
+		if err != nil {
+			// DF 비트가 설정된 상태에서 운영체제가 알고 있는 경로 MTU보다 큰 데이터그램은
+			// 보내기도 전에 EMSGSIZE 에러로 거부됨
+			if isMessageTooLong(err) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				if nErr, ok := err.(net.Error); ok && nErr.Timeout() {
+					break // 재시도
+				}
+				if isMessageTooLong(err) {
+					return false, nil
+				}
+				return false, err
+			}
+			// 이전에 보낸 다른 크기의 응답이 늦게 도착했을 수 있으므로 무시
+			if n == size && bytes.Equal(buf[:n], probe) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
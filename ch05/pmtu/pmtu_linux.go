@@ -0,0 +1,72 @@
+package pmtu
+
+import (
+	"errors"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// UDP 소켓에 DF(Don't Fragment) 비트를 설정하고, 경로 MTU 탐색을 활성화
+// 이후 운영체제가 알고 있는 경로 MTU보다 큰 데이터그램을 쓰면, 단편화 대신 EMSGSIZE 에러가 반환됨
+// 중간 라우터가 ICMP "fragmentation needed" 메시지를 보내면 커널은 경로 MTU를 갱신함
+func SetDontFragment(conn *net.UDPConn) error {
+	level, opt, val := unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_DO
+	if isIPv6(conn) {
+		level, opt, val = unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER,
+			unix.IPV6_PMTUDISC_DO
+	}
+
+	return control(conn, func(fd int) error {
+		return unix.SetsockoptInt(fd, level, opt, val)
+	})
+}
+
+// 커널이 알고 있는 현재 경로 MTU를 반환
+// 소켓이 net.DialUDP 등으로 연결(connect)된 상태여야 함
+func PathMTU(conn *net.UDPConn) (int, error) {
+	level, opt := unix.IPPROTO_IP, unix.IP_MTU
+	if isIPv6(conn) {
+		level, opt = unix.IPPROTO_IPV6, unix.IPV6_MTU
+	}
+
+	var mtu int
+	err := control(conn, func(fd int) error {
+		var err error
+		mtu, err = unix.GetsockoptInt(fd, level, opt)
+		return err
+	})
+
+	return mtu, err
+}
+
+func isMessageTooLong(err error) bool { return errors.Is(err, unix.EMSGSIZE) }
+
+func isIPv6(conn *net.UDPConn) bool {
+	addr, ok := conn.LocalAddr().(*net.UDPAddr)
+	return ok && addr.IP.To4() == nil
+}
+
+// 소켓의 파일 디스크립터로 f를 호출
+func control(conn *net.UDPConn, f func(fd int) error) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var opErr error
+	err = rc.Control(func(fd uintptr) {
+		// 시스템 콜이 인터럽트되면 다시 시도 (ch07의 auth.Allowed와 동일)
+		for {
+			opErr = f(int(fd))
+			if opErr != unix.EINTR {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return opErr
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package pmtu
+
+import (
+	"net"
+	"strings"
+)
+
+// 리눅스 이외의 운영체제에서는 지원하지 않음
+// 이 경우 Prober는 DF 비트 없이 동작하므로, 단편화되어 전달된 크기도 성공으로 판단할 수 있음
+func SetDontFragment(conn *net.UDPConn) error { return ErrUnsupported }
+
+func PathMTU(conn *net.UDPConn) (int, error) { return 0, ErrUnsupported }
+
+func isMessageTooLong(err error) bool {
+	return strings.Contains(err.Error(), "message too long")
+}
@@ -0,0 +1,122 @@
+package pmtu
+
+import (
+	"net"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// limit 바이트보다 큰 데이터그램은 조용히 버리는 에코 서버
+// ICMP 메시지 없이 큰 패킷을 버리는 경로(PMTU 블랙홀)를 흉내 냄
+func blackholeEcho(t *testing.T, limit int) net.Addr {
+	t.Helper()
+
+	s, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	go func() {
+		buf := make([]byte, MaxUDPPayload)
+		for {
+			n, addr, err := s.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n > limit {
+				continue
+			}
+			_, _ = s.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	return s.LocalAddr()
+}
+
+func dial(t *testing.T, addr net.Addr) *net.UDPConn {
+	t.Helper()
+
+	conn, err := net.DialUDP("udp", nil, addr.(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestProbeFindsBlackhole(t *testing.T) {
+	// 이더넷 MTU 1500에 해당하는 IPv4 UDP 페이로드 크기
+	limit := MaxPayload(1500, false)
+	conn := dial(t, blackholeEcho(t, limit))
+
+	p := Prober{Max: 9000, Timeout: 50 * time.Millisecond, Retries: 2}
+	size, err := p.Probe(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != limit {
+		t.Fatalf("expected %d; actual %d", limit, size)
+	}
+}
+
+func TestProbeNoReply(t *testing.T) {
+	// Min 크기조차 전달되지 않으면 에러를 반환해야 함
+	conn := dial(t, blackholeEcho(t, 100))
+
+	p := Prober{Min: 512, Max: 1024, Timeout: 20 * time.Millisecond,
+		Retries: 1}
+	if _, err := p.Probe(conn); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestDontFragment(t *testing.T) {
+	conn := dial(t, blackholeEcho(t, MaxUDPPayload))
+
+	err := SetDontFragment(conn)
+	if runtime.GOOS != "linux" {
+		if err != ErrUnsupported {
+			t.Fatalf("expected ErrUnsupported; actual %v", err)
+		}
+		t.Skip("path MTU discovery requires Linux")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mtu, err := PathMTU(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("path MTU: %d", mtu)
+
+	// DF 비트가 설정되면, 경로 MTU보다 큰 데이터그램은 단편화되지 않고 거부되어야 함
+	if largest := MaxPayload(mtu, false); largest < MaxUDPPayload {
+		_, err = conn.Write(make([]byte, largest+1))
+		if !isMessageTooLong(err) {
+			t.Fatalf("expected EMSGSIZE; actual %v", err)
+		}
+	}
+
+	// 루프백 인터페이스의 MTU 안에서는 최대 크기까지 왕복해야 함
+	p := Prober{Max: MaxPayload(mtu, false), Timeout: 50 * time.Millisecond}
+	size, err := p.Probe(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := min(MaxPayload(mtu, false), MaxUDPPayload); size != expected {
+		t.Fatalf("expected %d; actual %d", expected, size)
+	}
+}
+
+func TestMaxPayload(t *testing.T) {
+	if actual := MaxPayload(1500, false); actual != 1472 {
+		t.Errorf("IPv4: expected 1472; actual %d", actual)
+	}
+	if actual := MaxPayload(1500, true); actual != 1452 {
+		t.Errorf("IPv6: expected 1452; actual %d", actual)
+	}
+}
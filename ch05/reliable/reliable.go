@@ -0,0 +1,306 @@
+package reliable
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// 패킷 타입. 헤더의 첫 바이트
+const (
+	typeData uint8 = iota + 1 // 데이터 패킷
+	typeAck                   // 수신 확인 패킷
+)
+
+const (
+	// 1바이트 타입 + 4바이트 세션 ID + 4바이트 시퀀스 번호
+	headerSize = 9
+	// ch05의 에코 서버와 마찬가지로, 헤더를 포함해 1024 바이트까지만 다룸
+	MaxDatagramSize = 1024
+	// 페이로드의 최대 크기
+	MaxPayloadSize = MaxDatagramSize - headerSize
+
+	defaultRetransmitTimeout = 200 * time.Millisecond
+	defaultMaxRetries        = 10
+	// 중복 여부를 기억하는 시퀀스 번호의 범위
+	windowSize = 64
+)
+
+var (
+	ErrClosed          = errors.New("reliable: connection closed")
+	ErrNoAck           = errors.New("reliable: no acknowledgment received")
+	ErrPayloadTooLarge = errors.New("reliable: payload too large")
+)
+
+// Conn은 net.PacketConn 위에 시퀀스 번호, ACK, 재전송, 중복 제거를 얹은 신뢰성 있는 데이터그램 연결
+//
+// WriteTo 메서드는 상대방이 ACK를 보낼 때까지 블로킹되며, 응답이 없으면 RetransmitTimeout마다 재전송함
+// ReadFrom 메서드는 중복을 제거한 데이터 패킷만 반환하며, 받은 모든 데이터 패킷에는 ACK로 응답함
+// 한 송신자가 WriteTo를 순차적으로 호출하면 수신자는 보낸 순서대로 받지만,
+// 동시에 여러 WriteTo를 호출한 경우 순서는 보장하지 않음
+//
+// 연결마다 임의의 세션 ID를 헤더에 담아 보냄. 같은 주소의 상대방이 재시작해 시퀀스 번호가
+// 0부터 다시 시작하더라도, 세션 ID가 바뀌므로 수신자는 이전 수신 기록을 버리고 새로 받음
+type Conn struct {
+	RetransmitTimeout time.Duration // ACK를 기다릴 시간
+	MaxRetries        int           // 최대 재전송 횟수
+
+	pc      net.PacketConn
+	session uint32 // 이 연결이 보내는 데이터 패킷의 세션 ID
+
+	mu      sync.Mutex
+	nextSeq map[string]uint32        // 목적지 주소별 다음 시퀀스 번호
+	pending map[string]chan struct{} // ACK를 기다리는 패킷. 키: 주소 + 시퀀스 번호
+	seen    map[string]*window       // 송신자 주소별 수신 기록
+
+	incoming  chan datagram
+	closed    chan struct{}
+	closeOnce sync.Once
+	readErr   error
+}
+
+type datagram struct {
+	payload []byte
+	addr    net.Addr
+}
+
+// 네트워크 연결을 감싸 신뢰성 있는 연결 객체를 생성
+// 내부적으로 패킷을 읽는 고루틴을 시작하므로, 사용 후에는 반드시 Close 메서드를 호출해야 함
+func New(pc net.PacketConn) *Conn {
+	c := &Conn{
+		RetransmitTimeout: defaultRetransmitTimeout,
+		MaxRetries:        defaultMaxRetries,
+		pc:                pc,
+		session:           rand.Uint32(),
+		nextSeq:           make(map[string]uint32),
+		pending:           make(map[string]chan struct{}),
+		seen:              make(map[string]*window),
+		incoming:          make(chan datagram, windowSize),
+		closed:            make(chan struct{}),
+	}
+	go c.readLoop()
+
+	return c
+}
+
+// 페이로드를 addr로 전송하고, ACK를 받을 때까지 블로킹
+// MaxRetries 번 재전송한 후에도 ACK가 없으면 ErrNoAck를 반환
+func (c *Conn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if len(p) > MaxPayloadSize {
+		return 0, ErrPayloadTooLarge
+	}
+
+	c.mu.Lock()
+	dst := addr.String()
+	seq := c.nextSeq[dst]
+	c.nextSeq[dst] = seq + 1
+	key := ackKey(dst, seq)
+	acked := make(chan struct{})
+	c.pending[key] = acked
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}()
+
+	pkt := marshal(typeData, c.session, seq, p)
+	timer := time.NewTimer(c.RetransmitTimeout)
+	defer timer.Stop()
+
+	for i := 0; i <= c.MaxRetries; i++ {
+		if _, err := c.pc.WriteTo(pkt, addr); err != nil {
+			return 0, err
+		}
+
+		select {
+		case <-acked:
+			return len(p), nil
+		case <-c.closed:
+			return 0, ErrClosed
+		case <-timer.C:
+			// 타임아웃. 동일한 시퀀스 번호로 재전송
+			timer.Reset(c.RetransmitTimeout)
+		}
+	}
+
+	return 0, fmt.Errorf("sending seq %d to %s: %w", seq, dst, ErrNoAck)
+}
+
+// 다음 데이터 패킷의 페이로드를 p에 복사하고, 송신자 주소를 반환
+// 이미 받은 패킷의 재전송은 반환하지 않음
+func (c *Conn) ReadFrom(p []byte) (int, net.Addr, error) {
+	select {
+	case d := <-c.incoming:
+		return copy(p, d.payload), d.addr, nil
+	case <-c.closed:
+		// 연결이 닫히기 전에 도착한 패킷은 모두 반환
+		select {
+		case d := <-c.incoming:
+			return copy(p, d.payload), d.addr, nil
+		default:
+		}
+		if c.readErr != nil {
+			return 0, nil, c.readErr
+		}
+		return 0, nil, ErrClosed
+	}
+}
+
+func (c *Conn) LocalAddr() net.Addr { return c.pc.LocalAddr() }
+
+// 연결을 닫고, 블로킹 중인 WriteTo, ReadFrom 메서드를 모두 반환시킴
+func (c *Conn) Close() error {
+	err := c.pc.Close()
+	c.shutdown(nil)
+
+	return err
+}
+
+func (c *Conn) shutdown(err error) {
+	c.closeOnce.Do(func() {
+		c.readErr = err
+		close(c.closed)
+	})
+}
+
+// 네트워크 연결에서 패킷을 읽어, ACK는 대기 중인 WriteTo에 알리고 데이터는 incoming 채널로 보냄
+func (c *Conn) readLoop() {
+	buf := make([]byte, MaxDatagramSize)
+	for {
+		n, addr, err := c.pc.ReadFrom(buf)
+		if err != nil {
+			c.shutdown(err)
+			return
+		}
+
+		typ, session, seq, payload, err := unmarshal(buf[:n])
+		if err != nil {
+			continue // 형식이 맞지 않는 패킷은 무시
+		}
+
+		switch typ {
+		case typeAck:
+			if session != c.session {
+				continue // 이전 세션에서 보낸 패킷에 대한 ACK
+			}
+			c.mu.Lock()
+			if acked, ok := c.pending[ackKey(addr.String(), seq)]; ok {
+				close(acked)
+				// 같은 ACK가 중복으로 도착해도 다시 닫지 않도록 제거
+				delete(c.pending, ackKey(addr.String(), seq))
+			}
+			c.mu.Unlock()
+		case typeData:
+			src := addr.String()
+			c.mu.Lock()
+			w, ok := c.seen[src]
+			if !ok {
+				w = new(window)
+				c.seen[src] = w
+			}
+			if w.session != session {
+				// 송신자가 재시작했으므로 이전 세션의 수신 기록을 버림
+				*w = window{session: session}
+			}
+			dup := w.has(seq)
+			if !dup {
+				// 읽는 쪽이 느려 버퍼가 가득 찼다면, ACK를 보내지 않고 버림
+				// 송신자가 재전송할 것이므로 유실되지 않으며, ACK 처리가 막히지도 않음
+				select {
+				case c.incoming <- datagram{
+					payload: append([]byte(nil), payload...),
+					addr:    addr,
+				}:
+					w.add(seq)
+				default:
+					c.mu.Unlock()
+					continue
+				}
+			}
+			c.mu.Unlock()
+
+			// 중복 패킷이라도 ACK는 항상 다시 보냄
+			// 이전 ACK가 유실되어 송신자가 재전송했을 수 있기 때문
+			// ACK에는 데이터 패킷의 세션 ID를 그대로 담아, 송신자가 자신의 세션에 대한 ACK인지 확인하게 함
+			_, _ = c.pc.WriteTo(marshal(typeAck, session, seq, nil), addr)
+		}
+	}
+}
+
+func ackKey(addr string, seq uint32) string { return fmt.Sprintf("%s#%d", addr, seq) }
+
+func marshal(typ uint8, session, seq uint32, payload []byte) []byte {
+	b := make([]byte, headerSize+len(payload))
+	b[0] = typ
+	binary.BigEndian.PutUint32(b[1:5], session)
+	binary.BigEndian.PutUint32(b[5:headerSize], seq)
+	copy(b[headerSize:], payload)
+
+	return b
+}
+
+func unmarshal(b []byte) (uint8, uint32, uint32, []byte, error) {
+	if len(b) < headerSize {
+		return 0, 0, 0, nil, errors.New("short packet")
+	}
+	if b[0] != typeData && b[0] != typeAck {
+		return 0, 0, 0, nil, errors.New("unknown packet type")
+	}
+
+	return b[0], binary.BigEndian.Uint32(b[1:5]),
+		binary.BigEndian.Uint32(b[5:headerSize]), b[headerSize:], nil
+}
+
+// window는 송신자 하나로부터 받은 시퀀스 번호를 기억해 중복을 걸러냄
+// 가장 큰 시퀀스 번호와, 그보다 작은 windowSize 개의 번호에 대한 비트맵만 유지하므로 메모리 사용량이 일정함
+type window struct {
+	session uint32 // 송신자의 세션 ID
+	started bool
+	max     uint32
+	bits    uint64 // i번째 비트: max-i 번 패킷 수신 여부
+}
+
+// 이미 받은 시퀀스 번호이거나, 기억할 수 있는 범위보다 오래된 번호인지 확인
+func (w *window) has(seq uint32) bool {
+	if !w.started {
+		return false
+	}
+
+	// 시퀀스 번호의 오버플로를 고려해 부호 있는 차이로 비교
+	diff := int32(seq - w.max)
+	switch {
+	case diff > 0:
+		return false
+	case -diff >= windowSize:
+		return true
+	default:
+		return w.bits&(uint64(1)<<uint(-diff)) != 0
+	}
+}
+
+// 시퀀스 번호를 수신한 것으로 기록
+func (w *window) add(seq uint32) {
+	if !w.started {
+		w.started, w.max, w.bits = true, seq, 1
+		return
+	}
+
+	diff := int32(seq - w.max)
+	switch {
+	case diff > 0:
+		if diff >= windowSize {
+			w.bits = 1
+		} else {
+			w.bits = w.bits<<uint(diff) | 1
+		}
+		w.max = seq
+	case -diff < windowSize:
+		w.bits |= uint64(1) << uint(-diff)
+	}
+}
@@ -0,0 +1,304 @@
+package reliable
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 메모리상의 네트워크 주소
+type memAddr string
+
+func (a memAddr) Network() string { return "mem" }
+func (a memAddr) String() string  { return string(a) }
+
+type packet struct {
+	b    []byte
+	from net.Addr
+	to   string
+}
+
+// memNet은 패킷 유실과 순서 뒤바뀜을 시드값으로부터 결정적으로 흉내 내는 가짜 네트워크
+type memNet struct {
+	mu      sync.Mutex
+	rnd     *rand.Rand
+	loss    float64 // 패킷이 유실될 확률
+	reorder float64 // 패킷이 다음 패킷 뒤로 밀려날 확률
+	held    *packet
+	conns   map[string]*memConn
+	sent    int
+	dropped int
+}
+
+func newMemNet(seed int64, loss, reorder float64) *memNet {
+	return &memNet{
+		rnd:     rand.New(rand.NewSource(seed)),
+		loss:    loss,
+		reorder: reorder,
+		conns:   make(map[string]*memConn),
+	}
+}
+
+func (n *memNet) listen(addr string) *memConn {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	c := &memConn{
+		net:    n,
+		addr:   memAddr(addr),
+		in:     make(chan packet, 1024),
+		closed: make(chan struct{}),
+	}
+	n.conns[addr] = c
+
+	return c
+}
+
+func (n *memNet) send(p packet) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.sent++
+	if n.rnd.Float64() < n.loss {
+		n.dropped++
+		return
+	}
+	if n.held == nil && n.rnd.Float64() < n.reorder {
+		// 이 패킷은 잡아두었다가, 다음 패킷을 먼저 전달한 후에 전달
+		n.held = &p
+		return
+	}
+	n.deliverLocked(p)
+	if n.held != nil {
+		held := *n.held
+		n.held = nil
+		n.deliverLocked(held)
+	}
+}
+
+func (n *memNet) deliverLocked(p packet) {
+	if c, ok := n.conns[p.to]; ok {
+		select {
+		case c.in <- p:
+		default: // 수신 버퍼가 가득 차면 버림
+		}
+	}
+}
+
+// net.PacketConn 인터페이스를 구현하는 가짜 연결 객체
+type memConn struct {
+	net       *memNet
+	addr      memAddr
+	in        chan packet
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *memConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	select {
+	case pkt := <-c.in:
+		return copy(p, pkt.b), pkt.from, nil
+	case <-c.closed:
+		return 0, nil, net.ErrClosed
+	}
+}
+
+func (c *memConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+	c.net.send(packet{
+		b:    append([]byte(nil), p...),
+		from: c.addr,
+		to:   addr.String(),
+	})
+
+	return len(p), nil
+}
+
+func (c *memConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *memConn) LocalAddr() net.Addr                { return c.addr }
+func (c *memConn) SetDeadline(t time.Time) error      { return nil }
+func (c *memConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *memConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func TestReliableDeliveryWithLoss(t *testing.T) {
+	n := newMemNet(1, 0.3, 0.2)
+	sender := New(n.listen("sender"))
+	receiver := New(n.listen("receiver"))
+	defer func() { _ = sender.Close() }()
+	defer func() { _ = receiver.Close() }()
+	sender.RetransmitTimeout = 10 * time.Millisecond
+	sender.MaxRetries = 50
+
+	const count = 100
+	errs := make(chan error, 1)
+	go func() {
+		for i := 0; i < count; i++ {
+			msg := []byte(fmt.Sprintf("%03d", i))
+			if _, err := sender.WriteTo(msg, receiver.LocalAddr()); err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+
+	// 30% 유실과 순서 뒤바뀜에도 불구하고, 모든 메시지를 중복 없이 순서대로 받아야 함
+	buf := make([]byte, MaxPayloadSize)
+	for i := 0; i < count; i++ {
+		n, addr, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr.String() != "sender" {
+			t.Fatalf("unexpected sender %q", addr)
+		}
+		if expected := fmt.Sprintf("%03d", i); expected != string(buf[:n]) {
+			t.Fatalf("expected %q; actual %q", expected, buf[:n])
+		}
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	n.mu.Lock()
+	t.Logf("sent %d packets, dropped %d", n.sent, n.dropped)
+	n.mu.Unlock()
+}
+
+func TestReliableSuppressesDuplicates(t *testing.T) {
+	n := newMemNet(2, 0, 0)
+	raw := n.listen("raw")
+	receiver := New(n.listen("receiver"))
+	defer func() { _ = receiver.Close() }()
+
+	// ACK가 유실되어 송신자가 같은 패킷을 재전송한 상황을 흉내 냄
+	for _, seq := range []uint32{0, 0, 1, 0, 1, 2} {
+		_, _ = raw.WriteTo(marshal(typeData, 1, seq, []byte{byte('a' + seq)}),
+			receiver.LocalAddr())
+	}
+
+	buf := make([]byte, MaxPayloadSize)
+	var got []byte
+	for i := 0; i < 3; i++ {
+		n, _, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, buf[:n]...)
+	}
+	if !bytes.Equal(got, []byte("abc")) {
+		t.Fatalf("expected %q; actual %q", "abc", got)
+	}
+
+	// 중복 패킷을 포함한 모든 데이터 패킷에 ACK를 보내야 함
+	acks := 0
+	for acks < 6 {
+		n, _, err := raw.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ, _, _, _, err := unmarshal(buf[:n]); err == nil && typ == typeAck {
+			acks++
+		}
+	}
+}
+
+func TestReliableSenderRestart(t *testing.T) {
+	n := newMemNet(5, 0, 0)
+	receiver := New(n.listen("receiver"))
+	defer func() { _ = receiver.Close() }()
+
+	buf := make([]byte, MaxPayloadSize)
+	for _, msg := range []string{"before", "after"} {
+		// 같은 주소로 재시작한 송신자는 시퀀스 번호 0부터 다시 보냄
+		sender := New(n.listen("sender"))
+		if _, err := sender.WriteTo([]byte(msg), receiver.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		_ = sender.Close()
+
+		// 세션 ID가 바뀌었으므로, 이전 세션의 시퀀스 번호 0과 중복으로 취급하면 안 됨
+		n, addr, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := string(buf[:n]); actual != msg {
+			t.Fatalf("expected %q; actual %q", msg, actual)
+		}
+		if addr.String() != "sender" {
+			t.Fatalf("expected sender address; actual %q", addr)
+		}
+	}
+}
+
+func TestReliableNoAck(t *testing.T) {
+	// 모든 패킷이 유실되는 네트워크
+	n := newMemNet(3, 1, 0)
+	sender := New(n.listen("sender"))
+	defer func() { _ = sender.Close() }()
+	sender.RetransmitTimeout = time.Millisecond
+	sender.MaxRetries = 3
+
+	_, err := sender.WriteTo([]byte("ping"), memAddr("nowhere"))
+	if !errors.Is(err, ErrNoAck) {
+		t.Fatalf("expected ErrNoAck; actual %v", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.sent != 4 {
+		t.Errorf("expected 1 transmission and 3 retries; actual %d sends", n.sent)
+	}
+}
+
+func TestReliableClose(t *testing.T) {
+	n := newMemNet(4, 0, 0)
+	c := New(n.listen("c"))
+
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := c.ReadFrom(make([]byte, 1))
+		errs <- err
+	}()
+
+	_ = c.Close()
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ReadFrom did not return after Close")
+	}
+}
+
+func TestWindow(t *testing.T) {
+	var w window
+	for _, c := range []struct {
+		seq uint32
+		dup bool
+	}{
+		{5, false}, {5, true}, {7, false}, {6, false}, {6, true},
+		{100, false}, {7, true}, // 너무 오래된 번호는 중복으로 간주
+		{99, false}, {99, true},
+		{^uint32(0), true}, // 오버플로 직전의 번호는 과거로 간주
+	} {
+		if dup := w.has(c.seq); dup != c.dup {
+			t.Fatalf("seq %d: expected duplicate %t; actual %t", c.seq, c.dup, dup)
+		}
+		w.add(c.seq)
+	}
+}
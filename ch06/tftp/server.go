@@ -112,39 +112,48 @@ NEXTPACKET:
 			// 전송 완료를 결정하기 전, 클라이언트가 마지막 데이터 패킷을 성공적으로 수신했는지 확인해야 함
 			// 1) 클라이언트로부터 바이트를 읽은 후
 			// wait for the client's ACK packet
+			// 데드라인은 데이터 패킷을 보낼 때 한 번만 설정하므로, 아래에서 무시한 패킷이 대기 시간을 늘리지 않음
 			_ = conn.SetReadDeadline(time.Now().Add(s.Timeout))
 
-			_, err = conn.Read(buf)
-			if err != nil {
-				if nErr, ok := err.(net.Error); ok && nErr.Timeout() {
-					continue RETRY
-				}
+		ACK:
+			for {
+				_, err = conn.Read(buf)
+				if err != nil {
+					if nErr, ok := err.(net.Error); ok && nErr.Timeout() {
+						continue RETRY
+					}
 
-				log.Printf("[%s] waiting for ACK: %v", clientAddr, err)
-				return
-			}
-			// 2) Ack 객체나 Err 객체로 언마샬링을 시도
-			switch {
-			// Ack 객체로 언마샬링 되면?
-			case ackPkt.UnmarshalBinary(buf) == nil:
-				// 객체의 Block 값을 확인해 현재 데이터 패킷에 해당하는 블록 번호를 확인할 수 있음
-				// 블록 번호가 맞으면? NEXTPACKET 다시 순회
-				// 맞지 않으면? 현재의 데이터 패킷을 재전송
-				if uint16(ackPkt) == dataPkt.Block {
-					// received ACK; send next data packet
-					continue NEXTPACKET
+					log.Printf("[%s] waiting for ACK: %v", clientAddr, err)
+					return
+				}
+				// 2) Ack 객체나 Err 객체로 언마샬링을 시도
+				switch {
+				// Ack 객체로 언마샬링 되면?
+				case ackPkt.UnmarshalBinary(buf) == nil:
+					// 객체의 Block 값을 확인해 현재 데이터 패킷에 해당하는 블록 번호를 확인할 수 있음
+					// 블록 번호가 맞으면? NEXTPACKET 다시 순회
+					if uint16(ackPkt) == dataPkt.Block {
+						// received ACK; send next data packet
+						continue NEXTPACKET
+					}
+					// 맞지 않으면? 중복되거나 지연된 이전 블록의 ACK이므로 무시하고 계속 대기
+					// 여기서 바로 재전송하면, 중복 ACK마다 중복 데이터 패킷이 생겨
+					// 양쪽이 계속 같은 패킷을 주고받게 됨 (sorcerer's apprentice 증후군, RFC 1123 4.2.3.1)
+					// 현재 블록의 ACK가 끝내 오지 않으면, 타임아웃 후 재전송함
+					continue ACK
+				// Err 객체로 언마샬링 되면?
+				case errPkt.UnmarshalBinary(buf) == nil:
+					// 클라이언트가 에러를 반환했음을 알 수 있음
+					// 해당 사실을 로깅하고, 일찍이 함수를 반환
+					// 전체 페이로드를 보내기 전에 전송이 종료되었음을 의미함
+					// 이 경우, 복구가 불가능하므로 클라이언트는 파일을 다시 요청해야만 함
+					log.Printf("[%s] received error: %v",
+						clientAddr, errPkt.Message)
+					return
+				default:
+					// 알 수 없는 패킷도 무시하고 현재 블록의 ACK를 계속 기다림
+					log.Printf("[%s] bad packet", clientAddr)
 				}
-			// Err 객체로 언마샬링 되면?
-			case errPkt.UnmarshalBinary(buf) == nil:
-				// 클라이언트가 에러를 반환했음을 알 수 있음
-				// 해당 사실을 로깅하고, 일찍이 함수를 반환
-				// 전체 페이로드를 보내기 전에 전송이 종료되었음을 의미함
-				// 이 경우, 복구가 불가능하므로 클라이언트는 파일을 다시 요청해야만 함
-				log.Printf("[%s] received error: %v",
-					clientAddr, errPkt.Message)
-				return
-			default:
-				log.Printf("[%s] bad packet", clientAddr)
 			}
 		}
 
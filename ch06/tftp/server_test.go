@@ -5,8 +5,11 @@ import (
 	"io"
 	"io/ioutil"
 	"net"
+	"os"
 	"testing"
 	"time"
+
+	"github.com/awoodbeck/gnp/ch05/fault"
 )
 
 func TestServer(t *testing.T) {
@@ -101,3 +104,162 @@ func TestServer(t *testing.T) {
 		t.Fatal("sent payload not equal to received payload")
 	}
 }
+
+// 클라이언트가 보내는 ACK에 장애를 주입해, 서버가 재전송으로 복구하는지 확인
+// 손상된 패킷은 실제로는 UDP 체크섬에 의해 커널에서 버려지므로 유실과 같음
+// 따라서 손상(Corrupt)은 주입하지 않음. 손상된 OP 코드가 Err 패킷으로 해석되면 전송이 중단되기 때문
+func TestServerRecoversFromLostAcks(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		faults fault.Faults
+	}{
+		{"loss", fault.Faults{Seed: 1, Loss: 0.3}},
+		{"duplication and reordering",
+			fault.Faults{Seed: 3, Duplicate: 0.3, Reorder: 0.3}},
+		{"latency", fault.Faults{Seed: 4, Latency: 20 * time.Millisecond}},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			p1, err := os.ReadFile("./tftp/payload.svg")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 재시도 루프가 빠르게 동작하도록 타임아웃을 짧게 설정
+			s := Server{Payload: p1, Retries: 10, Timeout: 50 * time.Millisecond}
+			p2, stats := fetch(t, &s, c.faults)
+
+			if !bytes.Equal(p1, p2) {
+				t.Fatal("sent payload not equal to received payload")
+			}
+			t.Logf("%+v", stats)
+		})
+	}
+}
+
+// 모든 ACK가 유실되면, 서버는 Retries 번 전송한 후 포기해야 함
+func TestServerExhaustsRetries(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	s := Server{Payload: []byte("payload"), Retries: 3,
+		Timeout: 20 * time.Millisecond}
+	go func() { _ = s.Serve(conn) }()
+
+	raw, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fault.NewPacketConn(raw, fault.Faults{Loss: 1})
+	defer func() { _ = client.Close() }()
+
+	sendRRQ(t, raw, conn.LocalAddr())
+
+	// 첫 번째 데이터 패킷과 그 재전송을 모두 받아야 하며, 그 이후로는 아무것도 오지 않아야 함
+	received := 0
+	buf := make([]byte, DatagramSize)
+	for {
+		_ = client.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, addr, err := client.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+
+		var data Data
+		if err := data.UnmarshalBinary(buf[:n]); err != nil {
+			t.Fatal(err)
+		}
+		if data.Block != 1 {
+			t.Fatalf("expected block 1; actual %d", data.Block)
+		}
+		received++
+
+		b, _ := Ack(data.Block).MarshalBinary()
+		_, _ = client.WriteTo(b, addr) // 항상 유실됨
+	}
+
+	if received != int(s.Retries) {
+		t.Fatalf("expected %d transmissions; actual %d", s.Retries, received)
+	}
+}
+
+func sendRRQ(t *testing.T, client net.PacketConn, server net.Addr) {
+	t.Helper()
+
+	b, err := ReadReq{Filename: "test"}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.WriteTo(b, server); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 서버를 시작하고, 클라이언트의 쓰기에 장애를 주입한 채로 전체 페이로드를 내려받음
+// 읽기 요청은 장애 없이 보내고, 이후의 ACK에만 장애를 주입함
+func fetch(t *testing.T, s *Server, f fault.Faults) ([]byte, fault.Stats) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	go func() { _ = s.Serve(conn) }()
+
+	raw, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fault.NewPacketConn(raw, f)
+	defer func() { _ = client.Close() }()
+
+	sendRRQ(t, raw, conn.LocalAddr())
+
+	var (
+		p2   = new(bytes.Buffer)
+		next = uint16(1) // 다음으로 받아야 할 블록 번호
+		buf  = make([]byte, DatagramSize)
+	)
+	for {
+		_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, addr, err := client.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var data Data
+		if err = data.UnmarshalBinary(buf[:n]); err != nil {
+			t.Fatal(err)
+		}
+
+		// 재전송된 블록은 버리지만, 이전 ACK가 유실되었을 수 있으므로 ACK는 다시 보냄
+		if data.Block == next {
+			if _, err = io.Copy(p2, data.Payload); err != nil {
+				t.Fatal(err)
+			}
+			next++
+		}
+
+		b, err := Ack(data.Block).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = client.WriteTo(b, addr); err != nil {
+			t.Fatal(err)
+		}
+
+		if data.Block == next-1 && n < DatagramSize {
+			break
+		}
+	}
+
+	return p2.Bytes(), client.Stats()
+}
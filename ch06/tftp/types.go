@@ -13,8 +13,29 @@ const (
 	DatagramSize = 516
 	// 데이터 블록의 최대 크기. 4바이트 헤더 크기 제외
 	BlockSize = DatagramSize - 4
+
+	// RFC 2348 blksize 옵션으로 협상할 수 있는 블록 크기의 범위
+	MinBlockSize = 8
+	MaxBlockSize = 65464
 )
 
+// 단편화 없이 보낼 수 있는 UDP 페이로드의 최대 크기로부터, 클라이언트가 요청할 blksize 값을 계산
+// maxPayload는 pmtu.Prober나 pmtu.MaxPayload로 구할 수 있음
+// 예) 이더넷 MTU 1500 -> 1472 바이트 페이로드 -> 1468 바이트 블록
+// 이 서버는 아직 blksize 옵션을 협상하지 않으므로 항상 BlockSize를 사용함
+func BlockSizeFor(maxPayload int) int {
+	// 4바이트 헤더(OP 코드 + 블록 번호)를 제외
+	size := maxPayload - 4
+	switch {
+	case size < MinBlockSize:
+		return MinBlockSize
+	case size > MaxBlockSize:
+		return MaxBlockSize
+	}
+
+	return size
+}
+
 // TFTP 패킷 헤더의 첫 2바이트.
 // 작업을 나타내는 OP 코드(operation code)
 // 각 OP 코드는 2바이트의 양의 정수로 나타냄
@@ -6,6 +6,8 @@ import (
 	"encoding/binary"
 	"io/ioutil"
 	"testing"
+
+	"github.com/awoodbeck/gnp/ch05/pmtu"
 )
 
 func TestAck(t *testing.T) {
@@ -196,3 +198,19 @@ func TestReadReq(t *testing.T) {
 		t.Errorf("expected mode %q; actual mode %q", r1.Mode, r2.Mode)
 	}
 }
+
+func TestBlockSizeFor(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []struct{ payload, expected int }{
+		{pmtu.MaxPayload(1500, false), 1468},
+		{pmtu.MaxPayload(1500, true), 1448},
+		{DatagramSize, BlockSize},
+		{4, MinBlockSize},
+		{pmtu.MaxUDPPayload + 100, MaxBlockSize},
+	} {
+		if actual := BlockSizeFor(c.payload); actual != c.expected {
+			t.Errorf("%d: expected %d; actual %d", c.payload, c.expected, actual)
+		}
+	}
+}
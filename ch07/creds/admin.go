@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/awoodbeck/gnp/ch03/server"
+	"github.com/awoodbeck/gnp/ch07/creds/auth"
+	"github.com/awoodbeck/gnp/ch07/sockets"
+)
+
+// 허용된 피어에게 줄 단위 명령 세션을 제공하는 로컬 관리 서비스
+// 클라이언트가 명령을 한 줄씩 보내면, 서버는 결과를 여러 줄로 응답한 후
+// 성공하면 "OK", 실패하면 "ERR <이유>" 한 줄로 응답을 마침
+//
+//	status         서비스 가동 시간, 세션 수, 허용된 그룹을 출력
+//	reload groups  그룹 이름을 다시 조회해 정책을 갱신
+//	peers          연결된 피어의 인증 정보를 출력
+//	help           명령 목록을 출력
+//	quit           세션을 종료
+type admin struct {
+	groupNames []string       // 커맨드 라인에서 받은 그룹 이름
+	listener   *auth.Listener // reload 명령 시 정책을 교체할 리스너
+	started    time.Time
+
+	mu       sync.Mutex
+	sessions map[*auth.Conn]time.Time // 세션별 연결 시각
+}
+
+func newAdmin(l *auth.Listener, groupNames []string) *admin {
+	return &admin{
+		groupNames: groupNames,
+		listener:   l,
+		started:    time.Now(),
+		sessions:   make(map[*auth.Conn]time.Time),
+	}
+}
+
+// 그룹 이름을 다시 조회해 리스너의 정책을 교체하고, 허용된 그룹 ID의 수를 반환
+// /etc/group 파일이 바뀌었을 때 서비스를 재시작하지 않고 반영할 수 있음
+// 이미 연결된 세션은 끊지 않음
+func (a *admin) reload() int {
+	groups := parseGroupNames(a.groupNames)
+	a.listener.SetPolicy(auth.Policy{Groups: groups})
+
+	return len(groups)
+}
+
+// server.Handler 인터페이스 구현
+func (a *admin) ServeConn(ctx context.Context, conn net.Conn) {
+	if ac, ok := conn.(*auth.Conn); ok {
+		a.mu.Lock()
+		a.sessions[ac] = time.Now()
+		a.mu.Unlock()
+
+		defer func() {
+			a.mu.Lock()
+			delete(a.sessions, ac)
+			a.mu.Unlock()
+		}()
+	}
+
+	// 서버가 종료되면 블로킹된 Scan 메서드를 반환시키기 위해 연결을 닫음
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	w := bufio.NewWriter(conn)
+	_, _ = w.WriteString("Welcome\n")
+	if w.Flush() != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		quit, err := a.exec(w, fields)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "ERR %v\n", err)
+		} else {
+			_, _ = w.WriteString("OK\n")
+		}
+		if w.Flush() != nil || quit {
+			return
+		}
+	}
+}
+
+// 명령 하나를 실행하고 결과를 w에 씀. 세션을 종료해야 하면 true를 반환
+func (a *admin) exec(w io.Writer, fields []string) (bool, error) {
+	switch cmd, args := strings.ToLower(fields[0]), fields[1:]; {
+	case cmd == "status" && len(args) == 0:
+		a.status(w)
+	case cmd == "reload" && len(args) == 1 && strings.ToLower(args[0]) == "groups":
+		_, _ = fmt.Fprintf(w, "%d groups allowed\n", a.reload())
+	case cmd == "peers" && len(args) == 0:
+		a.peers(w)
+	case cmd == "help" && len(args) == 0:
+		_, _ = io.WriteString(w, "status\nreload groups\npeers\nhelp\nquit\n")
+	case cmd == "quit" && len(args) == 0:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q", strings.Join(fields, " "))
+	}
+
+	return false, nil
+}
+
+func (a *admin) status(w io.Writer) {
+	a.mu.Lock()
+	sessions := len(a.sessions)
+	a.mu.Unlock()
+
+	var gids []string
+	for gid := range a.listener.Policy().Groups {
+		gids = append(gids, groupName(gid))
+	}
+	sort.Strings(gids)
+
+	_, _ = fmt.Fprintf(w, "pid %d\n", os.Getpid())
+	_, _ = fmt.Fprintf(w, "uptime %s\n",
+		time.Since(a.started).Truncate(time.Second))
+	_, _ = fmt.Fprintf(w, "sessions %d\n", sessions)
+	_, _ = fmt.Fprintf(w, "groups %s\n", strings.Join(gids, " "))
+}
+
+// 연결된 순서대로 각 피어의 PID, 사용자, 그룹, 연결 시간을 출력
+func (a *admin) peers(w io.Writer) {
+	type session struct {
+		peer  auth.Peer
+		since time.Time
+	}
+
+	a.mu.Lock()
+	sessions := make([]session, 0, len(a.sessions))
+	for conn, since := range a.sessions {
+		sessions = append(sessions, session{peer: conn.Peer(), since: since})
+	}
+	a.mu.Unlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].since.Before(sessions[j].since)
+	})
+
+	for _, s := range sessions {
+		username := fmt.Sprint(s.peer.UID)
+		if u, err := user.LookupId(username); err == nil {
+			username = u.Username
+		}
+		_, _ = fmt.Fprintf(w, "pid=%d user=%s group=%s connected=%s\n",
+			s.peer.PID, username, groupName(fmt.Sprint(s.peer.GID)),
+			time.Since(s.since).Truncate(time.Second))
+	}
+}
+
+// 그룹 ID에 해당하는 그룹 이름을 반환. 조회할 수 없으면 그룹 ID를 그대로 반환
+func groupName(gid string) string {
+	if grp, err := user.LookupGroupId(gid); err == nil {
+		return grp.Name
+	}
+
+	return gid
+}
+
+// 주어진 경로에 유닉스 도메인 소켓 리스너를 생성하고, 소켓 파일의 권한과 소유 그룹을 설정
+// 피어 인증 정보 확인과 별개로, 파일 시스템 권한을 통해 연결할 수 있는 사용자 자체를 제한할 수 있음
+// group이 빈 문자열이면 소유 그룹을 변경하지 않음
+// 이전 프로세스가 남긴 소켓 파일은 지우고, 소켓 활성화로 상속받은 소켓이 있다면 그것을 사용
+// "@"로 시작하는 추상 네임스페이스 주소는 파일이 없으므로 권한을 설정하지 않음
+//
+// 바인딩과 권한 설정 사이에 다른 사용자가 연결하지 못하도록, 소켓 파일은 소유자만 접근할 수 있게 만든 후
+// 주어진 권한으로 완화함
+func listen(socket string, mode os.FileMode, group string) (*net.UnixListener,
+	error) {
+	var l net.Listener
+	err := withUmask(0o077, func() (err error) {
+		l, err = sockets.Listen("unix", socket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ul, ok := l.(*net.UnixListener)
+	if !ok {
+		_ = l.Close()
+		return nil, fmt.Errorf("%s is not a unix socket", socket)
+	}
+
+	if sockets.IsAbstract(socket) {
+		return ul, nil
+	}
+
+	err = setPermissions(socket, mode, group)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	return ul, nil
+}
+
+func setPermissions(socket string, mode os.FileMode, group string) error {
+	if group != "" {
+		grp, err := user.LookupGroup(group)
+		if err != nil {
+			return err
+		}
+
+		gid, err := strconv.Atoi(grp.Gid)
+		if err != nil {
+			return fmt.Errorf("parsing gid %q: %w", grp.Gid, err)
+		}
+
+		// 소유 그룹은 현재 사용자가 속한 그룹으로만 변경 가능
+		err = os.Chown(socket, -1, gid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Chmod(socket, mode)
+}
+
+// 관리 서비스를 시작. 반환된 서버의 Close 메서드를 호출하면 리스너와 모든 세션이 닫힘
+// auditLog가 nil이 아니면 모든 연결을 감사 로그로 기록
+func serveAdmin(l *net.UnixListener, groupNames []string,
+	auditLog *zap.Logger) (*server.Server, *admin) {
+	al := auth.NewListener(l, auth.Policy{Groups: parseGroupNames(groupNames)})
+	al.AuditLog = auditLog
+	// 허용되지 않은 피어는 거부 메시지를 받은 후 연결이 종료됨
+	al.OnDenied = func(conn *net.UnixConn, peer auth.Peer, reason string) {
+		_, _ = conn.Write([]byte("Access denied\n"))
+	}
+
+	a := newAdmin(al, groupNames)
+	srv := &server.Server{Handler: a}
+	go func() { _ = srv.Serve(al) }()
+
+	return srv, a
+}
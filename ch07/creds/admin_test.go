@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 관리 서비스에 연결해 첫 줄(환영 또는 거부 메시지)을 읽음
+func dialAdmin(t *testing.T, socket string) (net.Conn, *bufio.Reader, string) {
+	t.Helper()
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, r, strings.TrimSpace(line)
+}
+
+// 명령을 보내고, OK 또는 ERR 줄까지의 응답을 반환
+func command(t *testing.T, conn net.Conn, r *bufio.Reader,
+	cmd string) ([]string, string) {
+	t.Helper()
+
+	_, err := fmt.Fprintln(conn, cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "OK" || strings.HasPrefix(line, "ERR ") {
+			return lines, line
+		}
+		lines = append(lines, line)
+	}
+}
+
+func currentGroup(t *testing.T) string {
+	t.Helper()
+
+	grp, err := user.LookupGroupId(fmt.Sprint(os.Getgid()))
+	if err != nil {
+		t.Skipf("looking up current group: %v", err)
+	}
+
+	return grp.Name
+}
+
+func TestAdminSession(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "creds.sock")
+	l, err := listen(socket, 0600, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv, _ := serveAdmin(l, []string{currentGroup(t)}, nil)
+	defer func() { _ = srv.Close() }()
+
+	conn, r, welcome := dialAdmin(t, socket)
+	if welcome != "Welcome" {
+		t.Fatalf("expected %q; actual %q", "Welcome", welcome)
+	}
+
+	lines, result := command(t, conn, r, "status")
+	if result != "OK" || len(lines) != 4 || lines[2] != "sessions 1" {
+		t.Fatalf("unexpected status response: %q %q", lines, result)
+	}
+
+	lines, result = command(t, conn, r, "peers")
+	if pid := fmt.Sprintf("pid=%d ", os.Getpid()); result != "OK" ||
+		len(lines) != 1 || !strings.HasPrefix(lines[0], pid) {
+		t.Fatalf("unexpected peers response: %q %q", lines, result)
+	}
+
+	lines, result = command(t, conn, r, "reload groups")
+	if result != "OK" || len(lines) != 1 || lines[0] != "1 groups allowed" {
+		t.Fatalf("unexpected reload response: %q %q", lines, result)
+	}
+
+	_, result = command(t, conn, r, "shutdown")
+	if result != `ERR unknown command "shutdown"` {
+		t.Fatalf("unexpected response: %q", result)
+	}
+
+	// quit 명령 후 서버는 연결을 닫아야 함
+	_, result = command(t, conn, r, "quit")
+	if result != "OK" {
+		t.Fatalf("unexpected quit response: %q", result)
+	}
+	if _, err = r.ReadString('\n'); err == nil {
+		t.Fatal("connection still open after quit")
+	}
+}
+
+func TestAdminDenied(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "creds.sock")
+	l, err := listen(socket, 0600, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 허용된 그룹이 없으므로 모든 피어가 거부됨
+	srv, _ := serveAdmin(l, nil, nil)
+	defer func() { _ = srv.Close() }()
+
+	_, r, line := dialAdmin(t, socket)
+	if line != "Access denied" {
+		t.Fatalf("expected %q; actual %q", "Access denied", line)
+	}
+	if _, err = r.ReadString('\n'); err == nil {
+		t.Fatal("denied connection still open")
+	}
+}
+
+func TestListenPermissions(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "creds.sock")
+	l, err := listen(socket, 0640, currentGroup(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+
+	fi, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0640 {
+		t.Fatalf("expected mode %o; actual %o", 0640, perm)
+	}
+
+	if _, err = listen(filepath.Join(t.TempDir(), "x.sock"), 0600,
+		"no-such-group-gnp"); err == nil {
+		t.Fatal("expected an error for an unknown group")
+	}
+}
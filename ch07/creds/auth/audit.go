@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"fmt"
+	"os/user"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 감사 로그 레코드 하나에 해당하는 피어 정보
+// 피어 인증 정보에 더해, 사람이 읽기 쉬운 사용자 이름과 실행 파일 경로를 포함
+type AuditRecord struct {
+	Peer
+	// 피어 인증 정보를 읽지 못했는지 여부. true이면 Peer의 값은 의미가 없음
+	Unavailable bool
+	Username    string // 사용자 ID로 조회한 사용자 이름. 조회할 수 없으면 빈 문자열
+	Executable  string // 피어 프로세스의 실행 파일 경로. 알 수 없으면 빈 문자열
+	Decision
+}
+
+// 피어 인증 정보와 정책 평가 결과로 감사 로그 레코드를 생성
+// 인증 정보를 읽지 못했다면 peer로 nil을 전달. 0은 root의 사용자 ID이므로,
+// 빈 Peer로 기록하면 root가 연결한 것처럼 보이기 때문
+func NewAuditRecord(peer *Peer, d Decision) AuditRecord {
+	r := AuditRecord{Decision: d}
+	if peer == nil {
+		r.Unavailable = true
+		return r
+	}
+	r.Peer = *peer
+
+	if u, err := user.LookupId(fmt.Sprint(peer.UID)); err == nil {
+		r.Username = u.Username
+	}
+	// 피어 프로세스가 이미 종료되었거나 권한이 없다면 경로를 알 수 없음
+	r.Executable, _ = executable(peer.PID)
+
+	return r
+}
+
+// zapcore.ObjectMarshaler 인터페이스 구현
+func (r AuditRecord) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if r.Unavailable {
+		enc.AddString("credentials", "unavailable")
+	} else {
+		enc.AddInt32("pid", r.PID)
+		enc.AddUint32("uid", r.UID)
+		enc.AddUint32("gid", r.GID)
+		enc.AddString("user", r.Username)
+		enc.AddString("exe", r.Executable)
+	}
+	enc.AddBool("allowed", r.Allowed)
+	enc.AddString("reason", r.Reason)
+
+	return nil
+}
+
+// 감사 로그용 로거를 생성
+// 로그 파일을 다른 도구로 분석할 수 있도록, 한 줄에 하나의 JSON 객체를 기록
+// 감사 기록은 누락되면 안 되므로 샘플링하지 않음
+func NewAuditLogger(w zapcore.WriteSyncer) *zap.Logger {
+	encoderCfg := zapcore.EncoderConfig{
+		TimeKey:        "time",
+		MessageKey:     "msg",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+
+	return zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), w,
+		zapcore.InfoLevel))
+}
+
+// 크기가 maxSizeMB 메가바이트를 넘으면 교체(rotate)되는 로그 파일을 반환
+// 교체된 이전 파일은 최대 maxBackups개까지 압축해 보관
+// NewAuditLogger 함수에 zapcore.AddSync(RotatingFile(...))로 넘겨 사용
+func RotatingFile(path string, maxSizeMB, maxBackups int) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSizeMB,
+		MaxBackups: maxBackups,
+		LocalTime:  true,
+		Compress:   true,
+	}
+}
+
+// 피어 연결에 대한 감사 로그 레코드를 기록
+// 피어 인증 정보를 읽지 못했다면 peer로 nil을 전달
+func Audit(log *zap.Logger, local string, peer *Peer, d Decision) {
+	log.Info("peer connection",
+		zap.String("addr", local),
+		zap.Object("peer", NewAuditRecord(peer, d)),
+	)
+}
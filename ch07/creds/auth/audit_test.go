@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	f := RotatingFile(filepath.Join(dir, "audit.log"), 1, 2)
+	// 압축은 백그라운드에서 진행되므로, 테스트에서는 끔
+	f.Compress = false
+	defer func() { _ = f.Close() }()
+
+	log := NewAuditLogger(zapcore.AddSync(f))
+	for i := 0; i < 4; i++ {
+		Audit(log, "/tmp/creds.sock", &Peer{PID: 1}, Decision{Reason: "test"})
+		if err := f.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 교체할 때마다 이전 파일은 타임스탬프가 붙은 백업 파일로 이름이 바뀜
+	// 오래된 백업 파일은 백그라운드에서 지워지므로, 백업 파일이 생겼는지만 확인
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("expected rotated backups; actual %d files", len(entries))
+	}
+	for _, e := range entries {
+		t.Log(e.Name())
+	}
+}
+
+func TestAuditCredentialsUnavailable(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewAuditLogger(zapcore.AddSync(&buf))
+	Audit(log, "/tmp/creds.sock", nil,
+		Decision{Reason: "reading peer credentials: test"})
+
+	var rec struct {
+		Peer map[string]any `json:"peer"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// 인증 정보를 읽지 못한 연결이 uid 0(root)으로 기록되면 안 됨
+	if actual := rec.Peer["credentials"]; actual != "unavailable" {
+		t.Fatalf("expected %q; actual %q", "unavailable", actual)
+	}
+	for _, key := range []string{"pid", "uid", "gid", "user", "exe"} {
+		if v, ok := rec.Peer[key]; ok {
+			t.Errorf("unexpected %s %v in record: %s", key, v, buf.Bytes())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/user"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// 운영체제가 피어 인증 정보 조회를 지원하지 않을 때 반환
+var ErrUnsupported = errors.New("peer credentials not supported on this platform")
+
+// 유닉스 도메인 소켓 연결 상대방(peer)의 인증 정보
+// 리눅스에서는 SO_PEERCRED 소켓 옵션으로 얻어옴
+type Peer struct {
+	PID int32  // 상대방 프로세스 ID
+	UID uint32 // 상대방 사용자 ID
+	GID uint32 // 상대방 그룹 ID
+}
+
+// 허용할 피어의 목록
+// 셋 중 하나라도 일치하면 허용하며, 모두 비어 있으면 아무도 허용하지 않음
+type Policy struct {
+	Users  map[string]struct{} // 허용된 사용자 이름
+	Groups map[string]struct{} // 허용된 그룹 ID. Allowed 함수의 groups 매개변수와 동일
+	UIDs   map[uint32]struct{} // 허용된 사용자 ID
+}
+
+// 정책 평가 결과와 그 이유
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+// 피어가 정책에 의해 허용되는지 평가
+func (p Policy) Evaluate(peer Peer) Decision {
+	if len(p.Users) == 0 && len(p.Groups) == 0 && len(p.UIDs) == 0 {
+		return Decision{Reason: "empty policy"}
+	}
+
+	if _, ok := p.UIDs[peer.UID]; ok {
+		return Decision{Allowed: true, Reason: fmt.Sprintf("uid %d allowed", peer.UID)}
+	}
+
+	if len(p.Users) == 0 && len(p.Groups) == 0 {
+		return Decision{Reason: fmt.Sprintf("uid %d not allowed", peer.UID)}
+	}
+
+	// 피어의 사용자 ID로 사용자 정보를 조회
+	u, err := user.LookupId(fmt.Sprint(peer.UID))
+	if err != nil {
+		return Decision{Reason: fmt.Sprintf("looking up uid %d: %v", peer.UID, err)}
+	}
+
+	if _, ok := p.Users[u.Username]; ok {
+		return Decision{Allowed: true, Reason: fmt.Sprintf("user %s allowed", u.Username)}
+	}
+
+	if len(p.Groups) > 0 {
+		// 사용자가 속한 모든 그룹과, 연결 시점의 프로세스 그룹 ID를 허용된 그룹과 비교
+		gids, err := u.GroupIds()
+		if err != nil {
+			return Decision{Reason: fmt.Sprintf("looking up groups of %s: %v",
+				u.Username, err)}
+		}
+		gids = append(gids, fmt.Sprint(peer.GID))
+
+		for _, gid := range gids {
+			if _, ok := p.Groups[gid]; ok {
+				return Decision{Allowed: true, Reason: fmt.Sprintf("group %s allowed", gid)}
+			}
+		}
+	}
+
+	return Decision{Reason: fmt.Sprintf("user %s not allowed", u.Username)}
+}
+
+type peerKey struct{}
+
+// 피어 인증 정보를 담은 콘텍스트를 반환
+func NewContext(ctx context.Context, peer Peer) context.Context {
+	return context.WithValue(ctx, peerKey{}, peer)
+}
+
+// 콘텍스트에 담긴 피어 인증 정보를 반환
+func FromContext(ctx context.Context) (Peer, bool) {
+	peer, ok := ctx.Value(peerKey{}).(Peer)
+	return peer, ok
+}
+
+// Listener가 반환하는 연결 객체
+// 정책에 의해 허용된 피어의 인증 정보를 콘텍스트로 가지고 있음
+type Conn struct {
+	*net.UnixConn
+	ctx context.Context
+}
+
+func (c *Conn) Context() context.Context { return c.ctx }
+
+// 연결의 피어 인증 정보를 반환
+func (c *Conn) Peer() Peer {
+	peer, _ := FromContext(c.ctx)
+	return peer
+}
+
+// 인증 정보를 확인 중이거나 Accept 호출을 기다리는 연결의 최대 수
+// 이를 넘으면 기존 연결이 처리될 때까지 새 연결을 수락하지 않으므로,
+// server.Server의 MaxConns처럼 Accept 호출을 늦춰 연결 수를 제한하는 방식이 그대로 동작함
+const maxPendingConns = 64
+
+// Listener는 연결을 수락할 때마다 피어 인증 정보를 확인해, 정책에 맞는 피어의 연결만 반환하는 리스너
+// server.Server나 http.Server 등 net.Listener를 받는 어떤 서버에도 그대로 사용 가능
+//
+// 인증 정보 확인, 사용자와 그룹 조회, OnDenied 호출, 감사 로그 기록은 연결마다 별도의 고루틴에서 진행함
+// NSS 백엔드(LDAP 등)의 응답이 느려도 다른 클라이언트의 연결 수락을 막지 않으며,
+// 확인이 먼저 끝난 연결부터 Accept 메서드가 반환함
+type Listener struct {
+	*net.UnixListener
+
+	// 정책에 의해 거부된 연결마다 호출됨. 연결은 이 함수가 반환된 후 닫힘
+	// 거부 메시지를 쓰거나 로그를 남길 때 사용. nil이면 그냥 닫음
+	// 여러 고루틴에서 동시에 호출될 수 있음
+	OnDenied func(conn *net.UnixConn, peer Peer, reason string)
+
+	// nil이 아니면, 수락된 모든 연결의 피어 정보와 정책 평가 결과를 감사 로그로 기록
+	AuditLog *zap.Logger
+
+	mu     sync.RWMutex
+	policy Policy
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	pending   chan struct{} // 처리 중인 연결 수를 제한하는 세마포어
+	accepted  chan *Conn    // 정책에 의해 허용된 연결
+	errs      chan error    // 일시적인 Accept 에러
+	closing   chan struct{} // Close 메서드 호출 시 닫힘
+	done      chan struct{} // 수락 루프가 종료되면 닫힘
+	err       error         // 수락 루프를 종료시킨 에러. done이 닫힌 후에만 읽음
+}
+
+func NewListener(l *net.UnixListener, policy Policy) *Listener {
+	return &Listener{
+		UnixListener: l,
+		policy:       policy,
+		pending:      make(chan struct{}, maxPendingConns),
+		accepted:     make(chan *Conn),
+		errs:         make(chan error),
+		closing:      make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// 현재 적용 중인 정책을 반환
+func (l *Listener) Policy() Policy {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.policy
+}
+
+// 정책을 교체. Accept 메서드와 동시에 호출해도 안전하며, 이후에 수락되는 연결부터 적용됨
+// 이미 수락된 연결은 영향을 받지 않음
+func (l *Listener) SetPolicy(policy Policy) {
+	l.mu.Lock()
+	l.policy = policy
+	l.mu.Unlock()
+}
+
+// 허용된 피어의 연결을 받을 때까지 블로킹
+// 반환되는 연결은 *auth.Conn 타입
+// 처음 호출할 때 연결을 수락하는 고루틴을 시작함
+func (l *Listener) Accept() (net.Conn, error) {
+	l.startOnce.Do(func() { go l.acceptLoop() })
+
+	select {
+	case conn := <-l.accepted:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+// 리스너를 닫음. 인증 정보를 확인 중이던 연결도 모두 닫힘
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() { close(l.closing) })
+
+	return l.UnixListener.Close()
+}
+
+// 연결을 수락해 각각 별도의 고루틴에서 확인
+// 일시적인 에러는 Accept 메서드의 호출자에게 전달하고 계속 수락하며, 그 외의 에러가 발생하면 종료
+func (l *Listener) acceptLoop() {
+	defer close(l.done)
+
+	for {
+		select {
+		case l.pending <- struct{}{}:
+		case <-l.closing:
+			l.err = net.ErrClosed
+			return
+		}
+
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			<-l.pending
+
+			var ne interface{ Temporary() bool }
+			if !errors.As(err, &ne) || !ne.Temporary() {
+				l.err = err
+				return
+			}
+
+			select {
+			case l.errs <- err:
+			case <-l.closing:
+				l.err = net.ErrClosed
+				return
+			}
+			continue
+		}
+
+		go l.check(conn)
+	}
+}
+
+// 연결의 피어 인증 정보를 정책으로 평가해, 허용되면 Accept 메서드에 넘기고 거부되면 닫음
+func (l *Listener) check(conn *net.UnixConn) {
+	defer func() { <-l.pending }()
+
+	peer, err := PeerCredentials(conn)
+	decision := Decision{Reason: fmt.Sprintf("reading peer credentials: %v", err)}
+	if err == nil {
+		decision = l.Policy().Evaluate(peer)
+	}
+
+	if l.AuditLog != nil {
+		if err == nil {
+			Audit(l.AuditLog, l.Addr().String(), &peer, decision)
+		} else {
+			Audit(l.AuditLog, l.Addr().String(), nil, decision)
+		}
+	}
+
+	if decision.Allowed {
+		select {
+		case l.accepted <- &Conn{
+			UnixConn: conn,
+			ctx:      NewContext(context.Background(), peer),
+		}:
+			return
+		case <-l.done:
+			// 리스너가 닫혀 더 이상 Accept 메서드를 호출하지 않음
+			_ = conn.Close()
+			return
+		}
+	}
+
+	if l.OnDenied != nil {
+		l.OnDenied(conn, peer, decision.Reason)
+	}
+	_ = conn.Close()
+}
+
+// http.Server의 ConnContext 필드에 사용
+// Listener가 반환한 연결이라면, 요청의 콘텍스트에 피어 인증 정보를 담아 핸들러에서 FromContext로 꺼낼 수 있게 함
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	if ac, ok := c.(*Conn); ok {
+		return NewContext(ctx, ac.Peer())
+	}
+
+	return ctx
+}
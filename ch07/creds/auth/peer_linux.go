@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// 유닉스 도메인 소켓 연결로부터 피어의 인증 정보를 가져옴
+// 인증 정보는 상대방이 connect를 호출한 시점의 값
+func PeerCredentials(conn *net.UnixConn) (Peer, error) {
+	// 피어의 유닉스 인증 정보를 가져오려면 연결 객체의 파일 디스크립터가 필요함
+	// conn.File()과 달리 SyscallConn은 파일 디스크립터를 복제하지 않고, 연결을 블로킹 모드로 바꾸지도 않음
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return Peer{}, err
+	}
+
+	var (
+		ucred *unix.Ucred
+		opErr error
+	)
+	err = rc.Control(func(fd uintptr) {
+		for {
+			// 파일 디스크립터, 어느 프로토콜 계층에 속하였는지를 나타내는 상수 (unix.SOL_SOCKET), 옵션 값 (unix.SO_PEERCRED)를 넘겨줌
+			// 리눅스 커널에서 소켓 옵션 값을 얻어 오려면, 해당하는 옵션과 해당 옵션이 존재하는 계층 값이 모두 필요함
+			// unix.SOL_SOCKET : 리눅스 커널에서 소켓 계층의 옵션 값이 필요함을 알려줌
+			// unix.SO_PEERCRED : 리눅스 커널에 피어의 인증 정보가 필요하다고 알려줌
+			ucred, opErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET,
+				unix.SO_PEERCRED)
+			if opErr == unix.EINTR {
+				continue // syscall interrupted, try again
+			}
+
+			return
+		}
+	})
+	if err != nil {
+		return Peer{}, err
+	}
+	if opErr != nil {
+		return Peer{}, opErr
+	}
+
+	// unix.Ucred 객체에는
+	// 1) peer의 프로세스 정보
+	// 2) 사용자 ID, 그룹 ID 정보 가 있음
+	return Peer{PID: ucred.Pid, UID: ucred.Uid, GID: ucred.Gid}, nil
+}
+
+// 프로세스의 실행 파일 경로를 /proc 파일 시스템에서 읽음
+// 다른 사용자의 프로세스라면 root 권한이 필요함
+func executable(pid int32) (string, error) {
+	return os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+}
@@ -0,0 +1,274 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func listen(t *testing.T) *net.UnixListener {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "auth.sock")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	return l
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid := uint32(os.Getuid())
+	peer := Peer{PID: int32(os.Getpid()), UID: uid, GID: uint32(os.Getgid())}
+
+	for _, c := range []struct {
+		name    string
+		policy  Policy
+		allowed bool
+	}{
+		{"empty", Policy{}, false},
+		{"uid", Policy{UIDs: map[uint32]struct{}{uid: {}}}, true},
+		{"other uid", Policy{UIDs: map[uint32]struct{}{uid + 1: {}}}, false},
+		{"user", Policy{Users: map[string]struct{}{u.Username: {}}}, true},
+		{"group", Policy{Groups: map[string]struct{}{u.Gid: {}}}, true},
+		{"other group", Policy{Groups: map[string]struct{}{"-1": {}}}, false},
+	} {
+		d := c.policy.Evaluate(peer)
+		if d.Allowed != c.allowed {
+			t.Errorf("%s: expected allowed %t; actual %t (%s)", c.name,
+				c.allowed, d.Allowed, d.Reason)
+		}
+		if d.Reason == "" {
+			t.Errorf("%s: empty reason", c.name)
+		}
+	}
+}
+
+func TestListener(t *testing.T) {
+	uid := uint32(os.Getuid())
+	l := NewListener(listen(t), Policy{UIDs: map[uint32]struct{}{uid: {}}})
+
+	go func() {
+		conn, err := net.Dial("unix", l.Addr().String())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_ = conn.Close()
+	}()
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	ac, ok := conn.(*Conn)
+	if !ok {
+		t.Fatalf("expected *auth.Conn; actual %T", conn)
+	}
+	// 같은 프로세스에서 연결했으므로, 피어 인증 정보는 현재 프로세스의 정보와 같아야 함
+	peer, ok := FromContext(ac.Context())
+	if !ok {
+		t.Fatal("peer credentials missing from context")
+	}
+	if peer.UID != uid || peer.PID != int32(os.Getpid()) {
+		t.Fatalf("unexpected peer credentials: %+v", peer)
+	}
+}
+
+func TestListenerDenied(t *testing.T) {
+	uid := uint32(os.Getuid())
+	l := NewListener(listen(t), Policy{UIDs: map[uint32]struct{}{uid + 1: {}}})
+
+	denied := make(chan string, 1)
+	l.OnDenied = func(conn *net.UnixConn, peer Peer, reason string) {
+		_, _ = conn.Write([]byte("Access denied\n"))
+		denied <- reason
+		_ = l.Close()
+	}
+
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := l.Accept()
+		accepted <- err
+	}()
+
+	conn, err := net.Dial("unix", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// 거부 메시지를 받은 후 연결은 닫혀야 함
+	b, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "Access denied\n" {
+		t.Fatalf("unexpected response %q", b)
+	}
+	t.Logf("denied: %s", <-denied)
+
+	// 거부된 연결은 Accept 메서드가 반환하지 않음
+	if err = <-accepted; err == nil {
+		t.Fatal("denied connection was returned by Accept")
+	}
+}
+
+func TestListenerSlowCheck(t *testing.T) {
+	uid := uint32(os.Getuid())
+	l := NewListener(listen(t), Policy{UIDs: map[uint32]struct{}{uid + 1: {}}})
+
+	// 첫 번째 연결의 OnDenied 함수가 반환하지 않는 동안에도 두 번째 연결을 확인해야 함
+	denied := make(chan struct{}, 2)
+	release := make(chan struct{})
+	defer close(release)
+	l.OnDenied = func(*net.UnixConn, Peer, string) {
+		denied <- struct{}{}
+		<-release
+	}
+	go func() { _, _ = l.Accept() }()
+
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("unix", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = conn.Close() }()
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-denied:
+		case <-time.After(time.Second):
+			t.Fatalf("connection %d not checked while OnDenied blocked", i+1)
+		}
+	}
+}
+
+func TestConnContext(t *testing.T) {
+	l := NewListener(listen(t),
+		Policy{UIDs: map[uint32]struct{}{uint32(os.Getuid()): {}}})
+
+	// 유닉스 도메인 소켓 위에서 동작하는 HTTP 서버
+	// 핸들러는 요청의 콘텍스트로부터 피어 인증 정보를 꺼낼 수 있음
+	srv := &http.Server{
+		ConnContext: ConnContext,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, ok := FromContext(r.Context())
+			if !ok {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			_, _ = fmt.Fprintf(w, "uid %d", peer.UID)
+		}),
+	}
+	go func() { _ = srv.Serve(l) }()
+	defer func() { _ = srv.Close() }()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", l.Addr().String())
+			},
+		},
+	}
+
+	resp, err := client.Get("http://unix/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := fmt.Sprintf("uid %d", os.Getuid()); string(b) != expected {
+		t.Fatalf("expected %q; actual %q", expected, b)
+	}
+}
+
+func TestListenerAudit(t *testing.T) {
+	uid := uint32(os.Getuid())
+	var buf bytes.Buffer
+	l := NewListener(listen(t), Policy{UIDs: map[uint32]struct{}{uid + 1: {}}})
+	l.AuditLog = NewAuditLogger(zapcore.AddSync(&buf))
+
+	dial := func() {
+		conn, err := net.Dial("unix", l.Addr().String())
+		if err == nil {
+			_ = conn.Close()
+		}
+	}
+
+	// 첫 번째 연결은 거부되고, 정책을 바꾼 후의 두 번째 연결은 허용됨
+	l.OnDenied = func(*net.UnixConn, Peer, string) {
+		l.SetPolicy(Policy{UIDs: map[uint32]struct{}{uid: {}}})
+		go dial()
+	}
+	go dial()
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit records; actual %d:\n%s", len(lines),
+			buf.Bytes())
+	}
+	for i, allowed := range []bool{false, true} {
+		var rec struct {
+			Addr string `json:"addr"`
+			Peer struct {
+				PID     int32  `json:"pid"`
+				UID     uint32 `json:"uid"`
+				User    string `json:"user"`
+				Exe     string `json:"exe"`
+				Allowed bool   `json:"allowed"`
+				Reason  string `json:"reason"`
+			} `json:"peer"`
+		}
+		if err = json.Unmarshal(lines[i], &rec); err != nil {
+			t.Fatal(err)
+		}
+
+		p := rec.Peer
+		if p.PID != int32(os.Getpid()) || p.UID != uid ||
+			p.User != u.Username || p.Exe != exe || p.Allowed != allowed ||
+			p.Reason == "" || rec.Addr != l.Addr().String() {
+			t.Errorf("unexpected audit record %d: %s", i, lines[i])
+		}
+	}
+}
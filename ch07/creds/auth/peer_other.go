@@ -0,0 +1,13 @@
+//go:build !linux
+
+package auth
+
+import "net"
+
+// 리눅스 이외의 운영체제에서는 지원하지 않음
+// 따라서 Listener는 모든 연결을 거부함
+func PeerCredentials(conn *net.UnixConn) (Peer, error) {
+	return Peer{}, ErrUnsupported
+}
+
+func executable(int32) (string, error) { return "", ErrUnsupported }
@@ -4,21 +4,34 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net"
 	"os"
 	"os/signal"
 	"os/user"
 	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/awoodbeck/gnp/ch07/creds/auth"
 )
 
+var (
+	socket = flag.String("socket", filepath.Join(os.TempDir(), "creds.sock"),
+		"socket file path")
+	mode  = flag.String("mode", "0660", "socket file permissions (octal)")
+	group = flag.String("group", "", "group owning the socket file")
+	audit = flag.String("audit", "",
+		"audit log file path (rotated at 10 MB); empty to disable")
+)
+
 func init() {
 	flag.Usage = func() {
 		// 매개변수로 그룹 이름이 들어오기를 원함
 		// 허용된 그룹 이름 목록 맵에 각 그룹 ID에 해당하는 이름을 추가할 것
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(),
-			"Usage:\n\t%s <group names>\n", filepath.Base(os.Args[0]))
+			"Usage:\n\t%s [flags] <group names>\n", filepath.Base(os.Args[0]))
 		flag.PrintDefaults()
 	}
 }
@@ -43,55 +56,46 @@ func parseGroupNames(args []string) map[string]struct{} {
 
 func main() {
 	flag.Parse()
-	// 커맨드 라인 매개변수를 파싱해, 허용된 그룹 ID의 맵 생성
-	groups := parseGroupNames(flag.Args())
-	// /tmp/creds.sock 소켓 리스너 생성
-	socket := filepath.Join(os.TempDir(), "creds.sock")
-	addr, err := net.ResolveUnixAddr("unix", socket)
-	if err != nil {
-		log.Fatal(err)
+
+	perm, err := strconv.ParseUint(*mode, 8, 32)
+	if err != nil || perm > 0777 {
+		log.Fatalf("invalid mode %q", *mode)
 	}
 
-	s, err := net.ListenUnix("unix", addr)
+	// 소켓 리스너를 생성하고 파일 권한과 소유 그룹을 설정
+	s, err := listen(*socket, os.FileMode(perm), *group)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// 허용 여부와 관계없이 모든 연결을 감사 로그로 기록
+	var auditLog *zap.Logger
+	if *audit != "" {
+		f := auth.RotatingFile(*audit, 10, 5)
+		defer func() { _ = f.Close() }()
+		auditLog = auth.NewAuditLogger(zapcore.AddSync(f))
+	}
+
+	// 커맨드 라인 매개변수로 받은 그룹에 속한 피어에게만 관리 세션을 제공
+	srv, a := serveAdmin(s, flag.Args(), auditLog)
+
 	c := make(chan os.Signal, 1)
 	// 인터럽트 시그널로 서비스를 갑작스레 종료시키면
 	// net.ListenUnix 함수를 사용하였음에도 불구하고 Go 가 소켓 파일을 정리하고 제거하지 못하게 됨
-	// 먼저 시그널을 대기하고
-	signal.Notify(c, os.Interrupt)
-	// 별도의 고루틴에서 해당 시그널을 받은 후, 리스너를 종료하도록 함
+	// 먼저 시그널을 대기하고, 시그널을 받은 후 서버(리스너)를 종료하도록 함
 	// 이렇게 하면, Go에서 적절하게 소켓 파일을 처리할 수 있음
-	go func() {
-		<-c
-		_ = s.Close()
-	}()
+	// SIGHUP 시그널을 받으면 reload groups 명령과 같이 그룹을 다시 조회
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
-	fmt.Printf("Listening on %s ...\n", socket)
+	fmt.Printf("Listening on %s ...\n", *socket)
 
-	for {
-		// 리스너는 AcceptUnix 메서드를 이용해 연결 수립 요청을 받아들임
-		conn, err := s.AcceptUnix()
-		if err != nil {
-			break
-		}
-		// peer의 인증 정보가 허용되었는지 확인
-		if auth.Allowed(conn, groups) {
-			_, err = conn.Write([]byte("Welcome\n"))
-			// 허용된 peer는 계속 연결 유지
-			if err == nil {
-				// handle the connection in a goroutine here
-				continue
-			}
-		}
-		// 허용되지 않은 peer는 즉시 연결 종료
-		_, err = conn.Write([]byte("Access denied\n"))
-		if err != nil {
-			log.Println(err)
+	for sig := range c {
+		if sig == syscall.SIGHUP {
+			log.Printf("reloaded: %d groups allowed", a.reload())
+			continue
 		}
 
-		_ = conn.Close()
+		_ = srv.Close()
+		return
 	}
 }
@@ -0,0 +1,6 @@
+//go:build !darwin && !linux
+
+package main
+
+// 파일 생성 마스크를 지원하지 않는 운영체제에서는 그냥 fn을 호출
+func withUmask(_ int, fn func() error) error { return fn() }
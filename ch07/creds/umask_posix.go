@@ -0,0 +1,14 @@
+//go:build darwin || linux
+
+package main
+
+import "golang.org/x/sys/unix"
+
+// 파일 생성 마스크를 mask로 바꾼 상태에서 fn을 호출한 후 원래대로 되돌림
+// 마스크는 프로세스 전체에 적용되므로, 다른 고루틴이 파일을 만들지 않는 시작 시점에만 사용
+func withUmask(mask int, fn func() error) error {
+	old := unix.Umask(mask)
+	defer unix.Umask(old)
+
+	return fn()
+}
@@ -0,0 +1,36 @@
+package echo
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/awoodbeck/gnp/ch03/server"
+	"github.com/awoodbeck/gnp/ch07/creds/auth"
+)
+
+// streamingEchoServer와 같지만, 피어 인증 정보가 정책에 맞는 클라이언트에게만 에코잉하는 유닉스 도메인 소켓 서버
+// 정책에 맞지 않는 클라이언트의 연결은 auth.Listener가 수락 즉시 닫음
+func authorizedEchoServer(ctx context.Context, addr string,
+	policy auth.Policy) (net.Addr, error) {
+	uAddr, err := net.ResolveUnixAddr("unix", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := net.ListenUnix("unix", uAddr)
+	if err != nil {
+		return nil, fmt.Errorf("binding to unix %s: %w", addr, err)
+	}
+
+	// 리스너를 감싸기만 하면, 서버 코드는 그대로 사용할 수 있음
+	srv := &server.Server{Handler: server.Echo()}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	go func() { _ = srv.Serve(auth.NewListener(l, policy)) }()
+
+	return l.Addr(), nil
+}
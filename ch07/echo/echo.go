@@ -2,9 +2,11 @@ package echo
 
 import (
 	"context"
-	"fmt"
 	"net"
-	"os"
+
+	"github.com/awoodbeck/gnp/ch03/server"
+	"github.com/awoodbeck/gnp/ch05/batch"
+	"github.com/awoodbeck/gnp/ch07/sockets"
 )
 
 // 스트림 기반의 네트워크를 나타내는 문자열과 주소를 나타내는 문자열을 매개변수로 받음
@@ -12,58 +14,47 @@ import (
 // 콘텍스트와 네트워크 문자열, 주소 문자열을 받는 조금 더 일반적인 형태의 에코 서버를 만듦
 // -> tcp, unix, unixpacket과 같은 스트림 기반의 네트워크 타입을 네트워크 문자열로 전달 가능
 // 네트워크 타입에 따라 주소 문자열도 적용하면 됨
-// tcp -> IP주소:포트 / unix, unixpacket -> 존재하지 않는 파일 경로
+// tcp -> IP주소:포트 / unix, unixpacket -> 파일 경로 또는 "@"로 시작하는 추상 네임스페이스 주소 (리눅스)
+// 이전 프로세스가 남긴 소켓 파일은 지운 후 바인딩하며, 소켓 활성화로 상속받은 리스너가 있다면 그것을 사용
 // 콘텍스트 : 서버 종료를 알리는 시그널링을 위해 사용됨
 func streamingEchoServer(ctx context.Context, network string,
 	addr string) (net.Addr, error) {
 	// 에코 서버가 바인딩하게 되면, 소켓 파일이 생성됨
-	s, err := net.Listen(network, addr)
+	s, err := sockets.Listen(network, addr)
 	if err != nil {
-		return nil, fmt.Errorf("binding to %s %s: %w", network, addr, err)
+		return nil, err
 	}
 
-	go func() {
-		go func() {
-			// 함수 호출자가 콘텍스트를 취소하면, 서버는 종료됨
-			<-ctx.Done()
-			_ = s.Close()
-		}()
-		// 연결 요청 수신 대기
-		for {
-			// 서버가 연결을 수신하면
-			conn, err := s.Accept()
-			if err != nil {
-				return
-			}
-			// 수신받는 메시지를 별도의 고루틴에서 에코잉함
-			go func() {
-				defer func() { _ = conn.Close() }()
+	// Accept 루프와 연결별 고루틴 관리는 server 패키지에 맡기고, 에코잉 핸들러만 넘겨줌
+	srv := &server.Server{Handler: server.Echo()}
 
-				for {
-					buf := make([]byte, 1024)
-					n, err := conn.Read(buf)
-					if err != nil {
-						return
-					}
-
-					_, err = conn.Write(buf[:n])
-					if err != nil {
-						return
-					}
-				}
-			}()
-		}
+	go func() {
+		// 함수 호출자가 콘텍스트를 취소하면, 서버는 종료됨
+		<-ctx.Done()
+		_ = srv.Close()
 	}()
+	// 연결 요청 수신 대기
+	// 서버가 연결을 수신하면, 수신받는 메시지를 별도의 고루틴에서 에코잉함
+	go func() { _ = srv.Serve(s) }()
 
 	return s.Addr(), nil
 }
 
+// 데이터그램 기반의 에코 서버. udp 소켓이라면 리눅스에서 recvmmsg/sendmmsg 시스템 콜로
+// 여러 데이터그램을 한 번에 읽고 쓰며, unixgram 소켓이나 다른 운영체제에서는 하나씩 읽고 씀
 func datagramEchoServer(ctx context.Context, network string,
 	addr string) (net.Addr, error) {
+	return serveDatagrams(ctx, network, addr, batch.New)
+}
+
+// newConn으로 생성한 배치 연결 객체로 데이터그램을 에코잉
+// 벤치마크에서 배치 I/O를 사용하지 않는 서버와 비교할 때 newConn을 바꿔 사용
+func serveDatagrams(ctx context.Context, network, addr string,
+	newConn func(net.PacketConn) batch.Conn) (net.Addr, error) {
 	// net.PacketConn 객체를 반환하는 net.ListenPacket 함수를 호출
-	s, err := net.ListenPacket(network, addr)
+	s, err := sockets.ListenPacket(network, addr)
 	if err != nil {
-		return nil, fmt.Errorf("binding to %s %s: %w", network, addr, err)
+		return nil, err
 	}
 
 	go func() {
@@ -72,23 +63,11 @@ func datagramEchoServer(ctx context.Context, network string,
 			_ = s.Close()
 			// 코드 상에서 반드시 소켓 파일을 직접 지워야 함
 			// 그렇지 않으면, 동일한 소켓 파일 경로로 시도하는 바인딩이 모두 실패할 것
-			if network == "unixgram" {
-				_ = os.Remove(addr)
-			}
+			// 추상 네임스페이스 주소는 지울 파일이 없으므로 sockets.Remove 함수가 무시함
+			_ = sockets.Remove(network, addr)
 		}()
 
-		buf := make([]byte, 1024)
-		for {
-			n, clientAddr, err := s.ReadFrom(buf)
-			if err != nil {
-				return
-			}
-
-			_, err = s.WriteTo(buf[:n], clientAddr)
-			if err != nil {
-				return
-			}
-		}
+		_ = batch.Echo(newConn(s), 1024)
 	}()
 
 	return s.LocalAddr(), nil
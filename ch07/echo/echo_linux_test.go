@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/awoodbeck/gnp/ch07/creds/auth"
 )
 
 func TestEchoServerUnixPacket(t *testing.T) {
@@ -133,3 +135,98 @@ func BenchmarkEchoServerUnixPacket(b *testing.B) {
 		}
 	}
 }
+
+func TestAuthorizedEchoServer(t *testing.T) {
+	dir, err := os.MkdirTemp("", "echo_auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if rErr := os.RemoveAll(dir); rErr != nil {
+			t.Error(rErr)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uid := uint32(os.Getuid())
+	for _, c := range []struct {
+		name    string
+		policy  auth.Policy
+		allowed bool
+	}{
+		{"allowed", auth.Policy{UIDs: map[uint32]struct{}{uid: {}}}, true},
+		{"denied", auth.Policy{UIDs: map[uint32]struct{}{uid + 1: {}}}, false},
+	} {
+		socket := filepath.Join(dir, c.name+".sock")
+		rAddr, err := authorizedEchoServer(ctx, socket, c.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn, err := net.Dial("unix", rAddr.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		msg := []byte("ping")
+		_, err = conn.Write(msg)
+		if err != nil && c.allowed {
+			t.Fatal(err)
+		}
+
+		// 허용된 클라이언트는 응답을 받고, 거부된 클라이언트의 연결은 닫혀야 함
+		buf := make([]byte, 1024)
+		n, err := conn.Read(buf)
+		if c.allowed {
+			if err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+			if !bytes.Equal(msg, buf[:n]) {
+				t.Errorf("%s: expected reply %q; actual reply %q", c.name, msg,
+					buf[:n])
+			}
+		} else if err == nil {
+			t.Errorf("%s: expected connection to be closed; read %q", c.name,
+				buf[:n])
+		}
+		_ = conn.Close()
+	}
+}
+
+func TestEchoServerAbstract(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, network := range []string{"unix", "unixpacket"} {
+		// 추상 네임스페이스 주소는 파일을 만들지 않으므로 임시 디렉터리가 필요 없음
+		addr := fmt.Sprintf("@echo-%s-%d", network, os.Getpid())
+		rAddr, err := streamingEchoServer(ctx, network, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rAddr.String() != addr {
+			t.Fatalf("expected %q; actual %q", addr, rAddr)
+		}
+
+		conn, err := net.Dial(network, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		msg := []byte("ping")
+		if _, err = conn.Write(msg); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 1024)
+		n, err := conn.Read(buf)
+		_ = conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(msg, buf[:n]) {
+			t.Fatalf("expected reply %q; actual reply %q", msg, buf[:n])
+		}
+	}
+}
@@ -8,6 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"golang.org/x/net/ipv4"
+
+	"github.com/awoodbeck/gnp/ch05/batch"
 )
 
 func TestEchoServerUnix(t *testing.T) {
@@ -111,6 +116,84 @@ func BenchmarkEchoServerUDP(b *testing.B) {
 	}
 }
 
+// 클라이언트가 batch.Size 개씩 데이터그램을 보내고 응답을 받는 동안 초당 처리한 패킷 수를 측정
+// 핑퐁 방식의 BenchmarkEchoServerUDP는 한 번에 하나의 데이터그램만 오가므로 배치 처리의 이점이 드러나지 않음
+// single은 데이터그램을 하나씩 읽고 쓰는 서버, batch는 datagramEchoServer의 결과
+func BenchmarkEchoServerUDPBatch(b *testing.B) {
+	for _, c := range []struct {
+		name    string
+		newConn func(net.PacketConn) batch.Conn
+	}{
+		{"single", func(pc net.PacketConn) batch.Conn {
+			return batch.Single{PacketConn: pc}
+		}},
+		{"batch", batch.New},
+	} {
+		c := c
+		b.Run(c.name, func(b *testing.B) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			serverAddr, err := serveDatagrams(ctx, "udp", "127.0.0.1:", c.newConn)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			benchmarkEchoThroughput(b, serverAddr)
+		})
+	}
+}
+
+func benchmarkEchoThroughput(b *testing.B, serverAddr net.Addr) {
+	const burst = batch.Size
+
+	client, err := net.ListenPacket("udp", "127.0.0.1:")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	_ = client.(*net.UDPConn).SetReadBuffer(1 << 20)
+	// 클라이언트도 배치 I/O를 사용해, 측정값이 서버의 처리량에 좌우되도록 함
+	bc := batch.New(client)
+
+	out := make([]ipv4.Message, burst)
+	for i := range out {
+		out[i].Buffers = [][]byte{[]byte("ping")}
+		out[i].Addr = serverAddr
+	}
+	in := batch.NewMessages(burst, 1024)
+
+	var received, lost int
+	b.ResetTimer()
+	begin := time.Now()
+	for i := 0; i < b.N; i += burst {
+		for sent := 0; sent < burst; {
+			n, err := bc.WriteBatch(out[sent:], 0)
+			if err != nil {
+				b.Fatal(err)
+			}
+			sent += n
+		}
+
+		for got := 0; got < burst; {
+			_ = client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+			n, err := bc.ReadBatch(in[:burst-got], 0)
+			if err != nil {
+				// 응답이 유실되었다면 다음 묶음으로 넘어감
+				lost += burst - got
+				break
+			}
+			got += n
+			received += n
+		}
+	}
+	elapsed := time.Since(begin)
+	b.StopTimer()
+
+	b.ReportMetric(float64(received)/elapsed.Seconds(), "pkts/s")
+	b.ReportMetric(float64(lost), "lost")
+}
+
 func BenchmarkEchoServerTCP(b *testing.B) {
 	ctx, cancel := context.WithCancel(context.Background())
 	rAddr, err := streamingEchoServer(ctx, "tcp", "127.0.0.1:")
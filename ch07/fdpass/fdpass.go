@@ -0,0 +1,160 @@
+package fdpass
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/awoodbeck/gnp/ch07/creds/auth"
+)
+
+// 운영체제가 SCM_RIGHTS를 이용한 파일 디스크립터 전달을 지원하지 않을 때 반환
+var ErrUnsupported = errors.New("file descriptor passing not supported on this platform")
+
+// Handoff 소켓에 연결한 상대방이 현재 프로세스와 다른 사용자의 프로세스일 때 반환
+var ErrPeerMismatch = errors.New("peer is owned by another user")
+
+// 한 번의 메시지로 주고받을 수 있는 최대 파일 디스크립터 수
+// 리눅스 커널의 SCM_MAX_FD 값과 같음
+const MaxFiles = 253
+
+// 파일 디스크립터를 가진 리스너
+// *net.TCPListener와 *net.UnixListener가 이 인터페이스를 구현함
+type filer interface {
+	File() (*os.File, error)
+}
+
+// 리스너들의 파일 디스크립터를 유닉스 도메인 소켓 연결로 전송
+// 리스너는 복제된 파일 디스크립터로 전송되므로, 전송 후 송신 측에서 리스너를 닫아도
+// 수신 측의 리스너는 계속 연결을 수락할 수 있음
+func SendListeners(conn *net.UnixConn, listeners ...net.Listener) error {
+	files := make([]*os.File, 0, len(listeners))
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	for _, l := range listeners {
+		fl, ok := l.(filer)
+		if !ok {
+			return fmt.Errorf("listener %T has no file descriptor", l)
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+
+	return SendFiles(conn, files...)
+}
+
+// 유닉스 도메인 소켓 연결로부터 SendListeners로 전송된 리스너를 최대 n개까지 받음
+func ReceiveListeners(conn *net.UnixConn, n int) ([]net.Listener, error) {
+	files, err := ReceiveFiles(conn, n)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(files))
+	for i, f := range files {
+		// net.FileListener는 파일 디스크립터를 복제하므로, 받은 파일은 닫아야 함
+		l, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			for _, f := range files[i+1:] {
+				_ = f.Close()
+			}
+			return nil, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+		listeners = append(listeners, l)
+	}
+
+	return listeners, nil
+}
+
+// 무중단 재시작 시 이전 서버 프로세스가 호출
+// socket 경로에서 새로운 서버 프로세스의 연결을 기다렸다가 리스너들을 넘겨준 후 반환
+// 반환된 후에는 이전 프로세스가 리스너를 닫고 처리 중인 연결을 마무리하면 됨
+// 그동안 들어오는 연결 요청은 새로운 프로세스가 수락함
+//
+// 리스너는 현재 프로세스와 같은 사용자의 프로세스에만 넘겨줌
+// 다른 사용자의 연결은 닫고 계속 기다림
+func Handoff(socket string, listeners ...net.Listener) error {
+	addr, err := net.ResolveUnixAddr("unix", socket)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.ListenUnix("unix", addr)
+	if err != nil {
+		return fmt.Errorf("binding to unix %s: %w", socket, err)
+	}
+	defer func() { _ = l.Close() }()
+
+	for {
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			return err
+		}
+
+		err = checkPeer(conn, filepath.Dir(socket))
+		if err == nil {
+			defer func() { _ = conn.Close() }()
+			return SendListeners(conn, listeners...)
+		}
+		_ = conn.Close()
+
+		if !errors.Is(err, ErrPeerMismatch) {
+			return err
+		}
+	}
+}
+
+// 연결 상대방이 현재 프로세스와 같은 사용자의 프로세스인지 SO_PEERCRED로 확인
+// 피어 인증 정보를 얻을 수 없는 운영체제에서는, 소켓이 있는 디렉터리에
+// 다른 사용자가 접근할 수 없는 경우(예: 0700)에만 허용
+func checkPeer(conn *net.UnixConn, dir string) error {
+	peer, err := auth.PeerCredentials(conn)
+	if errors.Is(err, auth.ErrUnsupported) {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if perm := fi.Mode().Perm(); perm&0o077 != 0 {
+			return fmt.Errorf("%s is accessible by other users (%#o): %w",
+				dir, perm, auth.ErrUnsupported)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading peer credentials: %w", err)
+	}
+
+	if uid := os.Getuid(); peer.UID != uint32(uid) {
+		return fmt.Errorf("peer uid %d, expected %d: %w", peer.UID, uid,
+			ErrPeerMismatch)
+	}
+
+	return nil
+}
+
+// 무중단 재시작 시 새로운 서버 프로세스가 호출
+// Handoff를 호출한 이전 프로세스로부터 리스너들을 넘겨받음
+func Inherit(socket string) ([]net.Listener, error) {
+	conn, err := net.DialUnix("unix", nil,
+		&net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	return ReceiveListeners(conn, MaxFiles)
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !linux
+
+package fdpass
+
+import (
+	"net"
+	"os"
+)
+
+func SendFiles(*net.UnixConn, ...*os.File) error { return ErrUnsupported }
+
+func ReceiveFiles(*net.UnixConn, int) ([]*os.File, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,107 @@
+//go:build darwin || linux
+
+package fdpass
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// 파일들의 파일 디스크립터를 SCM_RIGHTS 제어 메시지로 전송
+// 커널은 수신 측 프로세스에 같은 파일을 가리키는 새로운 파일 디스크립터를 만들어 줌
+// 제어 메시지만 보낼 수는 없으므로, 파일 이름들을 데이터로 함께 보냄
+func SendFiles(conn *net.UnixConn, files ...*os.File) error {
+	if len(files) == 0 || len(files) > MaxFiles {
+		return fmt.Errorf("cannot send %d files", len(files))
+	}
+
+	fds := make([]int, len(files))
+	names := make([]string, len(files))
+	for i, f := range files {
+		fds[i] = int(f.Fd())
+		names[i] = f.Name()
+	}
+
+	_, _, err := conn.WriteMsgUnix([]byte(strings.Join(names, "\x00")),
+		unix.UnixRights(fds...), nil)
+
+	return err
+}
+
+// SendFiles로 전송된 파일을 최대 n개까지 받음
+// 받은 파일을 닫는 것은 호출자의 책임
+func ReceiveFiles(conn *net.UnixConn, n int) ([]*os.File, error) {
+	if n <= 0 || n > MaxFiles {
+		return nil, fmt.Errorf("cannot receive %d files", n)
+	}
+
+	buf := make([]byte, 4096)
+	// 파일 디스크립터 하나는 4바이트 정수
+	oob := make([]byte, unix.CmsgSpace(n*4))
+	bn, oobn, flags, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, err
+	}
+
+	fds, err := parseRights(oob[:oobn])
+	if err != nil {
+		return nil, err
+	}
+
+	// 버퍼가 작아 제어 메시지가 잘렸다면 일부 파일 디스크립터를 잃어버렸으므로 에러로 처리
+	// 제어 메시지 버퍼는 정렬 때문에 n개보다 많은 파일 디스크립터를 담을 수도 있으므로 개수도 확인
+	if flags&unix.MSG_CTRUNC != 0 || len(fds) > n {
+		closeFDs(fds)
+		return nil, errors.New("control message truncated: too many files")
+	}
+	if len(fds) == 0 {
+		return nil, errors.New("no files received")
+	}
+
+	names := strings.Split(string(buf[:bn]), "\x00")
+	files := make([]*os.File, len(fds))
+	for i, fd := range fds {
+		name := "fd"
+		if i < len(names) {
+			name = names[i]
+		}
+		files[i] = os.NewFile(uintptr(fd), name)
+	}
+
+	return files, nil
+}
+
+func parseRights(oob []byte) ([]int, error) {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+
+	var fds []int
+	for _, msg := range msgs {
+		if msg.Header.Level != unix.SOL_SOCKET ||
+			msg.Header.Type != unix.SCM_RIGHTS {
+			continue
+		}
+
+		rights, err := unix.ParseUnixRights(&msg)
+		if err != nil {
+			closeFDs(fds)
+			return nil, err
+		}
+		fds = append(fds, rights...)
+	}
+
+	return fds, nil
+}
+
+func closeFDs(fds []int) {
+	for _, fd := range fds {
+		_ = unix.Close(fd)
+	}
+}
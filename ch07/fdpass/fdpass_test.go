@@ -0,0 +1,183 @@
+//go:build darwin || linux
+
+package fdpass
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 서로 연결된 두 유닉스 도메인 소켓 연결을 반환
+func unixPair(t *testing.T) (*net.UnixConn, *net.UnixConn) {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "fdpass.sock")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+
+	client, err := net.DialUnix("unix", nil, l.Addr().(*net.UnixAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	server, err := l.AcceptUnix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	return client, server
+}
+
+func TestSendFiles(t *testing.T) {
+	client, server := unixPair(t)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	err = SendFiles(client, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 송신 측의 파일을 닫아도 수신 측이 받은 파일 디스크립터는 유효함
+	_ = w.Close()
+
+	files, err := ReceiveFiles(server, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file; actual %d", len(files))
+	}
+	if files[0].Name() != w.Name() {
+		t.Errorf("expected name %q; actual %q", w.Name(), files[0].Name())
+	}
+
+	// 받은 파이프의 쓰기 쪽으로 쓴 데이터를 원래 파이프의 읽기 쪽에서 읽을 수 있어야 함
+	_, err = files[0].Write([]byte("ping"))
+	_ = files[0].Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "ping" {
+		t.Fatalf("expected %q; actual %q", "ping", b)
+	}
+}
+
+func TestReceiveFilesTruncated(t *testing.T) {
+	client, server := unixPair(t)
+
+	f1, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f1.Close() }()
+	f2, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f2.Close() }()
+
+	// 받을 수 있는 것보다 많은 파일을 보내면 에러를 반환해야 함
+	if err = SendFiles(client, f1, f2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ReceiveFiles(server, 1); err == nil {
+		t.Fatal("expected an error for a truncated control message")
+	}
+}
+
+// 이전 서버와 새로운 서버 역할을 하는 두 고루틴 간에 TCP 리스너를 넘겨줌
+func TestListenerHandoff(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+
+	socket := filepath.Join(t.TempDir(), "handoff.sock")
+	done := make(chan error, 1)
+	go func() {
+		// 이전 서버: 리스너를 넘겨준 후 닫음
+		done <- Handoff(socket, l)
+		_ = l.Close()
+	}()
+
+	// 새로운 서버: 이전 서버의 Handoff 소켓이 준비될 때까지 재시도
+	var listeners []net.Listener
+	for i := 0; i < 100; i++ {
+		listeners, err = Inherit(socket)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(listeners) != 1 {
+		t.Fatalf("expected 1 listener; actual %d", len(listeners))
+	}
+	nl := listeners[0]
+	defer func() { _ = nl.Close() }()
+
+	if nl.Addr().String() != addr {
+		t.Fatalf("expected address %q; actual %q", addr, nl.Addr())
+	}
+
+	// 이전 서버가 리스너를 닫은 후에도 같은 주소로의 연결 요청을 새로운 서버가 수락함
+	go func() {
+		conn, err := nl.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err = conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err = io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "ping" {
+		t.Fatalf("expected %q; actual %q", "ping", buf)
+	}
+}
+
+func TestSendListenersUnsupported(t *testing.T) {
+	client, _ := unixPair(t)
+
+	// 파일 디스크립터가 없는 리스너는 전송할 수 없음
+	if err := SendListeners(client, fakeListener{}); err == nil {
+		t.Fatal("expected an error for a listener without a file descriptor")
+	}
+}
+
+type fakeListener struct{ net.Listener }
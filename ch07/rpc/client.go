@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// 연결이 닫힌 후 Call 메서드를 호출하거나, 응답을 기다리는 중 연결이 닫혔을 때 반환
+var ErrClosed = errors.New("client closed")
+
+// 호출자에게 전달할 응답
+type result struct {
+	payload []byte
+	err     error
+}
+
+// Client는 하나의 unixpacket 연결로 여러 요청을 동시에 보낼 수 있는 클라이언트
+// 각 요청은 요청 ID로 구분되므로, 응답은 요청을 보낸 순서와 관계없이 도착할 수 있음
+type Client struct {
+	// 콘텍스트에 데드라인이 없을 때 사용할 요청별 타임아웃. 0이면 타임아웃 없음
+	Timeout time.Duration
+
+	conn net.Conn
+
+	mu      sync.Mutex
+	nextID  uint32
+	pending map[uint32]chan result // 응답을 기다리는 요청
+	err     error                  // 연결이 닫힌 이유. nil이 아니면 더 이상 요청을 보낼 수 없음
+}
+
+// addr의 unixpacket 서버에 연결
+func Dial(addr string) (*Client, error) {
+	conn, err := net.Dial("unixpacket", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(conn), nil
+}
+
+// 이미 연결된 unixpacket 연결 객체로 클라이언트를 생성
+func NewClient(conn net.Conn) *Client {
+	c := &Client{
+		conn:    conn,
+		pending: make(map[uint32]chan result),
+	}
+	go c.readLoop()
+
+	return c
+}
+
+// 응답을 읽어 요청 ID에 해당하는 호출자에게 전달
+func (c *Client) readLoop() {
+	buf := make([]byte, MaxMessageSize)
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		var resp message
+		if err = resp.UnmarshalBinary(buf[:n]); err != nil {
+			c.fail(err)
+			return
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[resp.id]
+		delete(c.pending, resp.id)
+		c.mu.Unlock()
+		// 타임아웃으로 이미 포기한 요청의 응답은 버림
+		if !ok {
+			continue
+		}
+
+		switch resp.typ {
+		case typeResponse:
+			ch <- result{payload: resp.payload}
+		case typeError:
+			ch <- result{err: errors.New(string(resp.payload))}
+		default:
+			ch <- result{err: ErrBadMessage}
+		}
+	}
+}
+
+// 응답을 기다리는 모든 요청에 에러를 전달하고, 이후의 요청을 막음
+func (c *Client) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+	for id, ch := range c.pending {
+		ch <- result{err: c.err}
+		delete(c.pending, id)
+	}
+}
+
+// 연결을 닫음. 응답을 기다리는 요청은 ErrClosed 에러를 반환
+func (c *Client) Close() error {
+	err := c.conn.Close()
+	c.fail(net.ErrClosed)
+
+	return err
+}
+
+// method를 호출하고 응답을 resp에 디코딩
+// resp가 nil이면 응답을 버림
+// 서버의 핸들러가 에러를 반환하면 *RemoteError 타입의 에러를 반환
+func (c *Client) Call(ctx context.Context, method string, req, resp any) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	// 응답을 전달받을 채널. readLoop가 블로킹되지 않도록 버퍼를 둠
+	ch := make(chan result, 1)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	b, err := message{typ: typeRequest, id: id, method: method,
+		payload: payload}.MarshalBinary()
+	if err == nil {
+		_, err = c.conn.Write(b)
+	}
+	if err != nil {
+		c.forget(id)
+		return err
+	}
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			if errors.Is(r.err, ErrClosed) {
+				return r.err
+			}
+			return &RemoteError{Method: method, Message: r.err.Error()}
+		}
+		if resp == nil {
+			return nil
+		}
+		return json.Unmarshal(r.payload, resp)
+	case <-ctx.Done():
+		c.forget(id)
+		return ctx.Err()
+	}
+}
+
+func (c *Client) forget(id uint32) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// Client.Call 메서드의 타입이 지정된 버전
+func Call[Req, Resp any](ctx context.Context, c *Client, method string,
+	req Req) (Resp, error) {
+	var resp Resp
+	err := c.Call(ctx, method, req, &resp)
+
+	return resp, err
+}
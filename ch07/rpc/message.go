@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// unixpacket 소켓은 메시지 경계를 보존하므로, 메시지 하나가 요청 또는 응답 하나에 해당함
+// 길이 접두사나 구분자 없이 한 번의 Read로 메시지 전체를 읽을 수 있음
+//
+// 요청:  | 타입(1) | 요청 ID(4) | 메서드 이름 길이(1) | 메서드 이름 | JSON 페이로드 |
+// 응답:  | 타입(1) | 요청 ID(4) | JSON 페이로드 |
+// 에러:  | 타입(1) | 요청 ID(4) | 에러 메시지 |
+const (
+	typeRequest  = iota + 1 // 요청
+	typeResponse            // 성공 응답
+	typeError               // 에러 응답
+)
+
+const (
+	headerSize = 5
+
+	// 메시지의 최대 크기
+	// 수신 버퍼보다 큰 메시지는 잘리므로, 이보다 큰 요청이나 응답은 보낼 수 없음
+	MaxMessageSize = 64 * 1024
+)
+
+var (
+	// 메시지가 MaxMessageSize보다 클 때 반환
+	ErrTooLarge = errors.New("message too large")
+	// 형식이 잘못된 메시지를 받았을 때 반환
+	ErrBadMessage = errors.New("malformed message")
+)
+
+type message struct {
+	typ     uint8
+	id      uint32
+	method  string // 요청 메시지에만 사용
+	payload []byte
+}
+
+func (m message) MarshalBinary() ([]byte, error) {
+	size := headerSize + len(m.payload)
+	if m.typ == typeRequest {
+		if len(m.method) == 0 || len(m.method) > 255 {
+			return nil, fmt.Errorf("invalid method name %q", m.method)
+		}
+		size += 1 + len(m.method)
+	}
+	if size > MaxMessageSize {
+		return nil, ErrTooLarge
+	}
+
+	b := make([]byte, headerSize, size)
+	b[0] = m.typ
+	binary.BigEndian.PutUint32(b[1:], m.id)
+	if m.typ == typeRequest {
+		b = append(b, uint8(len(m.method)))
+		b = append(b, m.method...)
+	}
+
+	return append(b, m.payload...), nil
+}
+
+func (m *message) UnmarshalBinary(b []byte) error {
+	if len(b) < headerSize {
+		return ErrBadMessage
+	}
+
+	m.typ = b[0]
+	m.id = binary.BigEndian.Uint32(b[1:])
+	b = b[headerSize:]
+
+	switch m.typ {
+	case typeRequest:
+		if len(b) < 1 || len(b) < 1+int(b[0]) || b[0] == 0 {
+			return ErrBadMessage
+		}
+		m.method, b = string(b[1:1+b[0]]), b[1+b[0]:]
+	case typeResponse, typeError:
+	default:
+		return ErrBadMessage
+	}
+
+	// 읽기 버퍼를 재사용하므로 페이로드는 복사해 둠
+	m.payload = append([]byte(nil), b...)
+
+	return nil
+}
+
+// 서버의 핸들러가 반환한 에러
+// 에러 메시지만 전달되므로, 원래의 에러 타입은 알 수 없음
+type RemoteError struct {
+	Method  string
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Method, e.Message)
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	for _, m := range []message{
+		{typ: typeRequest, id: 1, method: "sum", payload: []byte(`{"A":1}`)},
+		{typ: typeResponse, id: 2, payload: []byte("3")},
+		{typ: typeError, id: 3, payload: []byte("unknown method")},
+	} {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual message
+		if err = actual.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, actual) {
+			t.Errorf("expected %+v; actual %+v", m, actual)
+		}
+	}
+
+	for _, b := range [][]byte{
+		nil,
+		{typeResponse, 0, 0, 0},
+		{typeRequest, 0, 0, 0, 1},         // 메서드 이름 없음
+		{typeRequest, 0, 0, 0, 1, 5, 'a'}, // 메서드 이름이 잘림
+		{9, 0, 0, 0, 1},                   // 알 수 없는 타입
+	} {
+		var m message
+		if err := m.UnmarshalBinary(b); !errors.Is(err, ErrBadMessage) {
+			t.Errorf("%v: expected ErrBadMessage; actual %v", b, err)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type sumRequest struct{ A, B int }
+
+type sleepRequest struct {
+	Delay time.Duration
+	Echo  string
+}
+
+// 테스트용 핸들러를 등록한 서버를 시작하고, 서버에 연결된 클라이언트를 반환
+func newTestClient(t testing.TB) *Client {
+	t.Helper()
+
+	s := NewServer()
+	Register(s, "sum", func(_ context.Context, r sumRequest) (int, error) {
+		return r.A + r.B, nil
+	})
+	Register(s, "sleep", func(ctx context.Context, r sleepRequest) (string, error) {
+		select {
+		case <-time.After(r.Delay):
+			return r.Echo, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	Register(s, "fail", func(context.Context, string) (string, error) {
+		return "", errors.New("handler failed")
+	})
+	Register(s, "panic", func(context.Context, string) (string, error) {
+		panic("handler panicked")
+	})
+	s.ErrorLog = log.New(io.Discard, "", 0)
+
+	return serveTestClient(t, s)
+}
+
+// 서버를 시작하고, 서버에 연결된 클라이언트를 반환
+func serveTestClient(t testing.TB, s *Server) *Client {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), fmt.Sprintf("%d.sock", os.Getpid()))
+	done := make(chan struct{})
+	go func() {
+		_ = s.ListenAndServe(socket)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		_ = s.Close()
+		<-done
+	})
+
+	// 서버가 바인딩할 때까지 재시도
+	var (
+		c   *Client
+		err error
+	)
+	for i := 0; i < 100; i++ {
+		if c, err = Dial(socket); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+func TestCall(t *testing.T) {
+	c := newTestClient(t)
+
+	sum, err := Call[sumRequest, int](context.Background(), c, "sum",
+		sumRequest{A: 2, B: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 5 {
+		t.Fatalf("expected 5; actual %d", sum)
+	}
+
+	var rErr *RemoteError
+	_, err = Call[string, string](context.Background(), c, "fail", "")
+	if !errors.As(err, &rErr) || rErr.Message != "handler failed" {
+		t.Fatalf("expected remote error; actual %v", err)
+	}
+
+	_, err = Call[string, string](context.Background(), c, "missing", "")
+	if !errors.As(err, &rErr) || rErr.Message != errUnknownMethod {
+		t.Fatalf("expected unknown method error; actual %v", err)
+	}
+
+	// 에러 후에도 같은 연결로 계속 호출할 수 있어야 함
+	if err = c.Call(context.Background(), "sum", sumRequest{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallPanic(t *testing.T) {
+	c := newTestClient(t)
+
+	var rErr *RemoteError
+	_, err := Call[string, string](context.Background(), c, "panic", "")
+	if !errors.As(err, &rErr) || rErr.Message != errInternal {
+		t.Fatalf("expected internal error; actual %v", err)
+	}
+
+	// 패닉 후에도 서버와 연결은 계속 동작해야 함
+	if err = c.Call(context.Background(), "sum", sumRequest{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDisconnectCancelsHandlers(t *testing.T) {
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+
+	s := NewServer()
+	Register(s, "block", func(ctx context.Context, _ string) (string, error) {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return "", ctx.Err()
+	})
+	c := serveTestClient(t, s)
+
+	go func() { _ = c.Call(context.Background(), "block", "", nil) }()
+	<-started
+	_ = c.Close()
+
+	// 클라이언트가 연결을 끊으면 처리 중인 핸들러의 콘텍스트가 취소되어야 함
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context not canceled after client disconnected")
+	}
+}
+
+func TestMaxRequests(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		active  int
+		maxSeen int
+	)
+
+	s := NewServer()
+	s.MaxRequests = 2
+	Register(s, "count", func(context.Context, string) (string, error) {
+		mu.Lock()
+		active++
+		maxSeen = max(maxSeen, active)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return "", nil
+	})
+	c := serveTestClient(t, s)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Call(context.Background(), "count", "", nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen != s.MaxRequests {
+		t.Fatalf("expected %d concurrent requests; actual %d", s.MaxRequests,
+			maxSeen)
+	}
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	c := newTestClient(t)
+
+	// 먼저 보낸 요청일수록 늦게 끝나므로, 응답은 요청의 역순으로 도착함
+	const calls = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			echo := fmt.Sprint(i)
+			resp, err := Call[sleepRequest, string](context.Background(), c,
+				"sleep", sleepRequest{
+					Delay: time.Duration(calls-i) * 10 * time.Millisecond,
+					Echo:  echo,
+				})
+			if err == nil && resp != echo {
+				err = fmt.Errorf("expected %q; actual %q", echo, resp)
+			}
+			errs <- err
+		}(i)
+	}
+
+	begin := time.Now()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// 요청이 동시에 처리되었다면, 가장 긴 요청의 처리 시간 정도만 걸림
+	if elapsed := time.Since(begin); elapsed > calls*10*time.Millisecond*3/2 {
+		t.Fatalf("calls were not concurrent: %s", elapsed)
+	}
+}
+
+func TestCallTimeout(t *testing.T) {
+	c := newTestClient(t)
+	c.Timeout = 50 * time.Millisecond
+
+	_, err := Call[sleepRequest, string](context.Background(), c, "sleep",
+		sleepRequest{Delay: time.Second})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded; actual %v", err)
+	}
+
+	// 콘텍스트의 데드라인이 클라이언트의 타임아웃보다 우선함
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := Call[sleepRequest, string](ctx, c, "sleep",
+		sleepRequest{Delay: 100 * time.Millisecond, Echo: "late"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != "late" {
+		t.Fatalf("expected %q; actual %q", "late", resp)
+	}
+}
+
+func TestClose(t *testing.T) {
+	c := newTestClient(t)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.Call(context.Background(), "sleep",
+			sleepRequest{Delay: time.Second}, nil)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	_ = c.Close()
+
+	// 응답을 기다리던 요청과 이후의 요청 모두 ErrClosed를 반환해야 함
+	if err := <-errs; !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed; actual %v", err)
+	}
+	if err := c.Call(context.Background(), "sum", sumRequest{},
+		nil); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed; actual %v", err)
+	}
+}
+
+func TestTooLarge(t *testing.T) {
+	c := newTestClient(t)
+
+	err := c.Call(context.Background(), "sleep",
+		sleepRequest{Echo: strings.Repeat("x", MaxMessageSize)}, nil)
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge; actual %v", err)
+	}
+}
+
+func BenchmarkCall(b *testing.B) {
+	c := newTestClient(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, err := Call[sumRequest, int](ctx, c, "sum", sumRequest{A: 1, B: 2})
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"runtime/debug"
+	"sync"
+
+	"github.com/awoodbeck/gnp/ch03/server"
+	"github.com/awoodbeck/gnp/ch07/sockets"
+)
+
+const (
+	// 등록되지 않은 메서드를 호출하면 클라이언트가 받는 에러 메시지
+	errUnknownMethod = "unknown method"
+	// 핸들러에서 패닉이 발생하면 클라이언트가 받는 에러 메시지
+	errInternal = "internal error"
+
+	defaultMaxRequests = 64
+)
+
+// JSON 페이로드를 디코딩해 핸들러를 호출하고, 결과를 JSON으로 인코딩해 반환
+type handler func(ctx context.Context, payload []byte) ([]byte, error)
+
+// Server는 unixpacket 리스너로 받은 요청을 등록된 핸들러로 처리
+// 하나의 연결로 들어온 요청도 각각 별도의 고루틴에서 처리하므로,
+// 오래 걸리는 요청이 같은 연결의 다른 요청을 막지 않음
+type Server struct {
+	// 연결 하나에서 동시에 처리하는 최대 요청 수
+	// 한도에 도달하면 처리 중인 요청이 끝날 때까지 해당 연결에서 더 읽지 않음
+	MaxRequests int
+
+	// 핸들러의 패닉 등을 기록할 로거. nil이면 log 패키지의 기본 로거를 사용
+	ErrorLog *log.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]handler
+	srv      server.Server
+}
+
+func NewServer() *Server {
+	s := &Server{
+		MaxRequests: defaultMaxRequests,
+		handlers:    make(map[string]handler),
+	}
+	s.srv.Handler = server.HandlerFunc(s.serveConn)
+
+	return s
+}
+
+// 메서드 이름에 타입이 지정된 핸들러를 등록
+// 요청과 응답은 JSON으로 인코딩되므로, Req와 Resp는 JSON으로 인코딩할 수 있는 타입이어야 함
+// 같은 이름으로 다시 등록하면 이전 핸들러를 대체함
+func Register[Req, Resp any](s *Server, method string,
+	h func(ctx context.Context, req Req) (Resp, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = func(ctx context.Context, payload []byte) ([]byte, error) {
+		var req Req
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return nil, fmt.Errorf("decoding request: %w", err)
+		}
+
+		resp, err := h(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(resp)
+	}
+}
+
+// addr에 unixpacket 리스너를 생성한 후 Serve 메서드를 호출
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := sockets.Listen("unixpacket", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+// 리스너로부터 연결을 수락해 요청을 처리
+// 항상 nil이 아닌 에러를 반환하며, Close 메서드 호출 후에는 server.ErrServerClosed를 반환
+func (s *Server) Serve(l net.Listener) error {
+	s.srv.ErrorLog = s.ErrorLog
+
+	return s.srv.Serve(l)
+}
+
+// 리스너와 모든 연결을 닫음. 처리 중인 핸들러의 콘텍스트는 취소됨
+func (s *Server) Close() error {
+	return s.srv.Close()
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		// 연결이 끊어지면 처리 중인 요청의 콘텍스트를 먼저 취소한 후, 핸들러가 반환하기를 기다림
+		cancel()
+		wg.Wait()
+	}()
+
+	// MaxRequests 만큼의 버퍼를 가진 채널을 세마포어로 사용
+	var sem chan struct{}
+	if s.MaxRequests > 0 {
+		sem = make(chan struct{}, s.MaxRequests)
+	}
+
+	buf := make([]byte, MaxMessageSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+
+		var req message
+		if err = req.UnmarshalBinary(buf[:n]); err != nil ||
+			req.typ != typeRequest {
+			// 형식이 잘못된 메시지를 보내는 클라이언트와는 연결을 끊음
+			return
+		}
+
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if sem != nil {
+					<-sem
+				}
+				wg.Done()
+			}()
+			// net.Conn의 Write 메서드는 여러 고루틴에서 동시에 호출해도 안전하며,
+			// unixpacket 소켓에서는 한 번의 Write가 하나의 메시지가 됨
+			_, _ = conn.Write(s.handle(ctx, req))
+		}()
+	}
+}
+
+// 요청을 처리하고 응답 메시지를 반환
+func (s *Server) handle(ctx context.Context, req message) []byte {
+	s.mu.RLock()
+	h, ok := s.handlers[req.method]
+	s.mu.RUnlock()
+
+	resp := message{typ: typeResponse, id: req.id}
+	var err error
+	if !ok {
+		err = errors.New(errUnknownMethod)
+	} else {
+		resp.payload, err = s.call(ctx, h, req)
+	}
+
+	if err == nil {
+		var b []byte
+		if b, err = resp.MarshalBinary(); err == nil {
+			return b
+		}
+	}
+
+	// 에러 메시지는 잘라서라도 보냄
+	msg := err.Error()
+	if len(msg) > MaxMessageSize-headerSize {
+		msg = msg[:MaxMessageSize-headerSize]
+	}
+	b, _ := message{typ: typeError, id: req.id, payload: []byte(msg)}.MarshalBinary()
+
+	return b
+}
+
+// 핸들러를 호출하고, 패닉이 발생하면 복구해 에러로 반환
+// 요청 하나의 패닉이 서버 전체를 종료시키지 않으며, 클라이언트는 에러 응답을 받음
+func (s *Server) call(ctx context.Context, h handler, req message) (
+	payload []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logf("panic handling %q: %v\n%s", req.method, r, debug.Stack())
+			payload, err = nil, errors.New(errInternal)
+		}
+	}()
+
+	return h(ctx, req.payload)
+}
+
+func (s *Server) logf(format string, v ...any) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
@@ -0,0 +1,217 @@
+package sockets
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// systemd 방식의 소켓 활성화에서 상속받는 첫 번째 파일 디스크립터
+// 0, 1, 2는 표준 입출력이므로 3부터 시작
+const listenFDsStart = 3
+
+// 소켓 활성화로 상속받은 소켓
+type activated struct {
+	file *os.File
+	name string // LISTEN_FDNAMES에 지정된 이름
+
+	l  net.Listener   // 스트림 소켓이면 nil이 아님
+	pc net.PacketConn // 데이터그램 소켓이면 nil이 아님
+}
+
+var (
+	activateOnce sync.Once
+	activatedMu  sync.Mutex
+	sockets      []*activated
+	activateErr  error
+)
+
+// systemd 방식의 소켓 활성화로 상속받은 파일들을 반환
+// 서비스 관리자는 소켓을 미리 바인딩해 두고, 첫 번째 연결 요청이 들어오면 서비스를 실행하면서
+// 그 소켓을 파일 디스크립터 3번부터 넘겨주고 LISTEN_PID, LISTEN_FDS, LISTEN_FDNAMES 환경 변수를 설정함
+// 자식 프로세스가 같은 소켓을 다시 상속받지 않도록 환경 변수는 읽은 후 지움
+// 여러 번 호출해도 처음 읽은 결과를 반환
+func Activated() ([]*os.File, error) {
+	if err := activate(); err != nil {
+		return nil, err
+	}
+
+	files := make([]*os.File, len(sockets))
+	for i, s := range sockets {
+		files[i] = s.file
+	}
+
+	return files, nil
+}
+
+func activate() error {
+	activateOnce.Do(func() {
+		sockets, activateErr = parseEnv()
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	})
+
+	return activateErr
+}
+
+func parseEnv() ([]*activated, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pid == "" || fds == "" {
+		return nil, nil
+	}
+	// 다른 프로세스를 위해 설정된 환경 변수를 상속받은 경우는 무시
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+
+	var names []string
+	if v := os.Getenv("LISTEN_FDNAMES"); v != "" {
+		names = strings.Split(v, ":")
+	}
+
+	socks := make([]*activated, n)
+	for i := range socks {
+		name := fmt.Sprintf("LISTEN_FD_%d", listenFDsStart+i)
+		if i < len(names) {
+			name = names[i]
+		}
+		socks[i] = &activated{
+			file: os.NewFile(uintptr(listenFDsStart+i), name),
+			name: name,
+		}
+	}
+
+	return socks, nil
+}
+
+// network, addr과 일치하는 활성화된 스트림 소켓의 리스너를 반환. 없으면 nil을 반환
+func activatedListener(network, addr string) (net.Listener, error) {
+	if err := activate(); err != nil {
+		return nil, err
+	}
+
+	activatedMu.Lock()
+	defer activatedMu.Unlock()
+
+	for _, s := range sockets {
+		if s.l == nil && s.pc == nil {
+			if err := s.open(); err != nil {
+				return nil, err
+			}
+		}
+		if s.l != nil && s.matches(s.l.Addr(), network, addr) {
+			return s.l, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// network, addr과 일치하는 활성화된 데이터그램 소켓을 반환. 없으면 nil을 반환
+func activatedPacketConn(network, addr string) (net.PacketConn, error) {
+	if err := activate(); err != nil {
+		return nil, err
+	}
+
+	activatedMu.Lock()
+	defer activatedMu.Unlock()
+
+	for _, s := range sockets {
+		if s.l == nil && s.pc == nil {
+			if err := s.open(); err != nil {
+				return nil, err
+			}
+		}
+		if s.pc != nil && s.matches(s.pc.LocalAddr(), network, addr) {
+			return s.pc, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func isActivated(network, addr string) bool {
+	if activate() != nil {
+		return false
+	}
+
+	activatedMu.Lock()
+	defer activatedMu.Unlock()
+
+	for _, s := range sockets {
+		switch {
+		case s.l != nil && s.matches(s.l.Addr(), network, addr):
+			return true
+		case s.pc != nil && s.matches(s.pc.LocalAddr(), network, addr):
+			return true
+		}
+	}
+
+	return false
+}
+
+// 파일 디스크립터로부터 리스너 또는 패킷 연결 객체를 생성
+// 소켓 타입을 알 수 없으므로 리스너를 먼저 시도하고, 실패하면 패킷 연결 객체로 시도
+func (s *activated) open() error {
+	l, err := net.FileListener(s.file)
+	if err == nil {
+		s.l = l
+		return nil
+	}
+
+	pc, pErr := net.FilePacketConn(s.file)
+	if pErr != nil {
+		return fmt.Errorf("socket %s: %w", s.name, err)
+	}
+	s.pc = pc
+
+	return nil
+}
+
+// 활성화된 소켓이 주어진 network, addr에 해당하는지 확인
+// addr이 LISTEN_FDNAMES의 이름과 같거나, 바인딩된 주소와 같으면 일치
+// TCP, UDP 주소는 호스트를 생략하거나("":8080) 와일드카드 주소를 사용하면 포트만 비교
+func (s *activated) matches(bound net.Addr, network, addr string) bool {
+	if addr == s.name {
+		return true
+	}
+	if !sameFamily(bound.Network(), network) {
+		return false
+	}
+	if bound.String() == addr {
+		return true
+	}
+
+	var ip net.IP
+	var port int
+	switch a := bound.(type) {
+	case *net.TCPAddr:
+		ip, port = a.IP, a.Port
+	case *net.UDPAddr:
+		ip, port = a.IP, a.Port
+	default:
+		return false
+	}
+
+	host, p, err := net.SplitHostPort(addr)
+	if err != nil || p != strconv.Itoa(port) {
+		return false
+	}
+	want := net.ParseIP(host)
+
+	return host == "" || (want != nil && (want.IsUnspecified() || want.Equal(ip)))
+}
+
+// tcp4, tcp6 등의 네트워크 문자열을 tcp와 같은 계열로 취급
+func sameFamily(a, b string) bool {
+	return strings.TrimRight(a, "46") == strings.TrimRight(b, "46")
+}
@@ -0,0 +1,131 @@
+package sockets
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// 이미 실행 중인 서버가 소켓 파일을 사용하고 있을 때 반환
+var ErrInUse = errors.New("socket in use by a running server")
+
+// 주소가 리눅스 추상 네임스페이스 주소("@"로 시작)인지 여부
+// 추상 네임스페이스 소켓은 파일 시스템에 소켓 파일을 만들지 않으므로,
+// 프로세스가 비정상 종료되어도 지워야 할 파일이 남지 않음
+func IsAbstract(addr string) bool {
+	return strings.HasPrefix(addr, "@")
+}
+
+func isUnix(network string) bool {
+	switch network {
+	case "unix", "unixgram", "unixpacket":
+		return true
+	}
+
+	return false
+}
+
+// net.Listen과 같지만, 다음을 추가로 처리
+// 1) 소켓 활성화로 상속받은 리스너 중 network, addr과 일치하는 것이 있으면 바인딩하지 않고 그것을 반환
+// 2) 유닉스 도메인 소켓 주소가 "@"로 시작하면 추상 네임스페이스에 바인딩 (리눅스 전용)
+// 3) 이전 프로세스가 남긴 소켓 파일이 있다면, 사용 중이 아님을 확인한 후 지우고 바인딩
+func Listen(network, addr string) (net.Listener, error) {
+	l, err := activatedListener(network, addr)
+	if err != nil || l != nil {
+		return l, err
+	}
+
+	err = prepare(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err = net.Listen(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("binding to %s %s: %w", network, addr, err)
+	}
+
+	return l, nil
+}
+
+// net.ListenPacket에 대해 Listen 함수와 같은 처리를 함
+// unixgram 소켓은 닫아도 소켓 파일이 지워지지 않으므로, 닫은 후 Remove 함수를 호출해야 함
+func ListenPacket(network, addr string) (net.PacketConn, error) {
+	pc, err := activatedPacketConn(network, addr)
+	if err != nil || pc != nil {
+		return pc, err
+	}
+
+	err = prepare(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err = net.ListenPacket(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("binding to %s %s: %w", network, addr, err)
+	}
+
+	return pc, nil
+}
+
+// 유닉스 도메인 소켓 파일을 지움
+// 추상 네임스페이스 주소나 소켓 활성화로 상속받은 소켓, 유닉스 도메인 소켓이 아닌 주소는 무시
+func Remove(network, addr string) error {
+	if !isUnix(network) || addr == "" || IsAbstract(addr) || isActivated(network, addr) {
+		return nil
+	}
+
+	err := os.Remove(addr)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// 바인딩 전에 주소를 검사하고, 남아 있는 소켓 파일을 정리
+func prepare(network, addr string) error {
+	if !isUnix(network) {
+		return nil
+	}
+	if IsAbstract(addr) {
+		if !abstractSupported {
+			return fmt.Errorf("abstract socket %s: %w", addr, errors.ErrUnsupported)
+		}
+		return nil
+	}
+
+	return removeStale(network, addr)
+}
+
+// 경로에 소켓 파일이 있다면 연결을 시도해, 연결을 받는 프로세스가 없는 경우에만 파일을 지움
+// 소켓 파일이 아닌 파일은 지우지 않으며, 이 경우 바인딩이 실패함
+func removeStale(network, addr string) error {
+	fi, err := os.Lstat(addr)
+	if err != nil || fi.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+
+	conn, err := net.Dial(network, addr)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s: %w", addr, ErrInUse)
+	}
+
+	// 소켓 파일에 바인딩된 소켓이 없으면 ECONNREFUSED 에러가 발생함
+	// 그 외의 에러(권한 부족 등)라면 판단할 수 없으므로 파일을 그대로 둠
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return nil
+	}
+
+	err = os.Remove(addr)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing stale socket: %w", err)
+	}
+
+	return nil
+}
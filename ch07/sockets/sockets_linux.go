@@ -0,0 +1,5 @@
+package sockets
+
+// 리눅스는 추상 네임스페이스 유닉스 도메인 소켓을 지원
+// Go의 net 패키지는 "@"로 시작하는 주소를 추상 네임스페이스 주소로 변환함
+const abstractSupported = true
@@ -0,0 +1,52 @@
+package sockets
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestListenAbstract(t *testing.T) {
+	addr := fmt.Sprintf("@gnp-%d.sock", os.Getpid())
+
+	l, err := Listen("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+
+	if l.Addr().String() != addr {
+		t.Fatalf("expected %q; actual %q", addr, l.Addr())
+	}
+	// 추상 네임스페이스 소켓은 파일을 만들지 않음
+	if _, err = os.Stat(addr); err == nil {
+		t.Fatal("abstract socket created a file")
+	}
+	if err = Remove("unix", addr); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			_, _ = conn.Write([]byte("ping"))
+			_ = conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	buf := make([]byte, 4)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "ping" {
+		t.Fatalf("expected %q; actual %q", "ping", buf[:n])
+	}
+}
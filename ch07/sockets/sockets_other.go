@@ -0,0 +1,5 @@
+//go:build !linux
+
+package sockets
+
+const abstractSupported = false
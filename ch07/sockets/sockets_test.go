@@ -0,0 +1,167 @@
+//go:build darwin || linux
+
+package sockets
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestListenRemovesStaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "stale.sock")
+
+	// 비정상 종료된 프로세스처럼 소켓 파일을 남겨 둠
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetUnlinkOnClose(false)
+	_ = l.Close()
+	if _, err = os.Stat(socket); err != nil {
+		t.Fatal(err)
+	}
+
+	// net.Listen은 남아 있는 소켓 파일 때문에 실패함
+	if _, err = net.Listen("unix", socket); err == nil {
+		t.Fatal("expected net.Listen to fail on a stale socket")
+	}
+
+	l2, err := Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l2.Close() }()
+
+	// 사용 중인 소켓 파일은 지우지 않아야 함
+	_, err = Listen("unix", socket)
+	if !errors.Is(err, ErrInUse) {
+		t.Fatalf("expected ErrInUse; actual %v", err)
+	}
+}
+
+func TestListenPacketRemovesStaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "stale.sock")
+
+	// unixgram 소켓은 닫아도 소켓 파일이 남음
+	pc, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = pc.Close()
+
+	pc, err = ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = pc.Close()
+
+	if err = Remove("unixgram", socket); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(socket); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("socket file not removed: %v", err)
+	}
+}
+
+func TestListenKeepsRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regular")
+	if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// 소켓 파일이 아닌 파일은 지우지 않고, 바인딩에 실패해야 함
+	if _, err := Listen("unix", path); err == nil {
+		t.Fatal("expected an error binding to a regular file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("regular file removed: %v", err)
+	}
+}
+
+func TestActivation(t *testing.T) {
+	if os.Getenv("GNP_ACTIVATION_HELPER") != "" {
+		activationHelper()
+		return
+	}
+
+	// 서비스 관리자 역할: 소켓을 미리 바인딩한 후, 파일 디스크립터 3번으로 넘겨주며 자식 프로세스를 실행
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestActivation$")
+	cmd.Env = append(os.Environ(), "GNP_ACTIVATION_HELPER=1",
+		"LISTEN_FDS=1", "LISTEN_FDNAMES=echo",
+		"GNP_ACTIVATION_ADDR="+l.Addr().String())
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Stderr = os.Stderr
+	if err = cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cmd.Wait() }()
+
+	// 자식 프로세스가 상속받은 리스너로 연결을 수락해야 함
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	b, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "activated echo"; string(b) != expected {
+		t.Fatalf("expected %q; actual %q", expected, b)
+	}
+}
+
+// 소켓 활성화된 자식 프로세스
+// LISTEN_PID는 실행 전에 알 수 없으므로 직접 설정
+func activationHelper() {
+	_ = os.Setenv("LISTEN_PID", fmt.Sprint(os.Getpid()))
+
+	fail := func(err error) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// 주소와 이름 모두 같은 리스너를 반환해야 함
+	l, err := Listen("tcp", os.Getenv("GNP_ACTIVATION_ADDR"))
+	if err != nil {
+		fail(err)
+	}
+	byName, err := Listen("tcp", "echo")
+	if err != nil {
+		fail(err)
+	}
+	if l != byName {
+		fail(errors.New("listeners differ"))
+	}
+	if os.Getenv("LISTEN_FDS") != "" {
+		fail(errors.New("LISTEN_FDS not unset"))
+	}
+
+	conn, err := l.Accept()
+	if err != nil {
+		fail(err)
+	}
+	_, _ = conn.Write([]byte("activated echo"))
+	_ = conn.Close()
+	os.Exit(0)
+}
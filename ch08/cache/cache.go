@@ -0,0 +1,370 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 응답이 캐시에서 왔는지를 나타내는 헤더
+// 캐시는 이 헤더를 응답에 추가하며, 값은 다음 중 하나
+//
+//	HIT          저장된 응답이 신선해 서버에 요청하지 않음
+//	REVALIDATED  저장된 응답이 오래되어 서버에 확인했고, 서버가 304 Not Modified를 응답
+//	MISS         서버의 응답을 그대로 반환 (저장될 수도 있음)
+const XCache = "X-Cache"
+
+// 기본 최대 body 크기. 이보다 큰 응답은 저장하지 않음
+const DefaultMaxBodySize = 16 << 20
+
+// Transport는 RFC 9111의 규칙에 따라 GET 요청의 응답을 저장하고 재사용하는 http.RoundTripper
+// 사용자 한 명의 클라이언트에서 사용하는 사설(private) 캐시이므로, Cache-Control: private 응답도 저장함
+//
+// 저장된 응답이 신선하면 서버에 요청하지 않고 반환하며, 오래되었다면 ETag와 Last-Modified 헤더로
+// 조건부 요청(If-None-Match, If-Modified-Since)을 보내 304 Not Modified 응답을 받으면 저장된 body를 재사용함
+// 응답의 Vary 헤더에 나열된 요청 헤더 값이 다른 요청에는 저장된 응답을 사용하지 않음
+// HEAD 요청은 저장된 GET 응답으로 응답할 수 있으며, POST 같은 안전하지 않은 메서드의 요청이
+// 성공하면 해당 URL의 저장된 응답을 무효화함
+type Transport struct {
+	// 실제 요청을 보낼 RoundTripper. nil이면 http.DefaultTransport를 사용
+	Transport http.RoundTripper
+
+	// 응답을 저장할 저장소
+	Store Store
+
+	// body가 이보다 큰 응답은 저장하지 않음. 0이면 DefaultMaxBodySize를 사용
+	MaxBodySize int64
+
+	// 현재 시각. nil이면 time.Now를 사용. 테스트에서 가짜 시계를 주입할 때 사용
+	Now func() time.Time
+}
+
+// 저장소를 사용하는 캐싱 Transport를 반환
+func NewTransport(store Store) *Transport {
+	return &Transport{Store: store}
+}
+
+// Transport를 사용하는 http.Client를 반환
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+
+	return http.DefaultTransport
+}
+
+func (t *Transport) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+
+	return time.Now()
+}
+
+func (t *Transport) maxBodySize() int64 {
+	if t.MaxBodySize > 0 {
+		return t.MaxBodySize
+	}
+
+	return DefaultMaxBodySize
+}
+
+func cacheKey(req *http.Request) string {
+	return req.URL.String()
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := cacheKey(req)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.transport().RoundTrip(req)
+		// 안전하지 않은 메서드의 요청이 성공하면, 리소스가 바뀌었을 수 있으므로 저장된 응답을 지움 (RFC 9111 4.4)
+		if err == nil && resp.StatusCode < 400 && !safe(req.Method) {
+			t.Store.Delete(key)
+		}
+		return resp, err
+	}
+
+	// 호출자가 직접 조건부 요청이나 범위 요청을 보냈다면 캐시를 거치지 않음
+	if req.Header.Get("If-None-Match") != "" ||
+		req.Header.Get("If-Modified-Since") != "" ||
+		req.Header.Get("Range") != "" {
+		return t.transport().RoundTrip(req)
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	e, cached := t.lookup(req, key)
+
+	if cached != nil {
+		respCC := parseCacheControl(cached.Header)
+		age := currentAge(cached, e, t.now())
+
+		if t.usable(reqCC, respCC, age, freshnessLifetime(cached, respCC)) {
+			cached.Header.Set("Age", fmt.Sprint(int64(age/time.Second)))
+			cached.Header.Set(XCache, "HIT")
+			return cached, nil
+		}
+	} else if reqCC.has("only-if-cached") {
+		// 저장된 응답이 없으면 서버에 요청하지 않고 504를 응답 (RFC 9111 5.2.1.7)
+		return gatewayTimeout(req), nil
+	}
+
+	outReq := req
+	if cached != nil {
+		outReq = conditional(req, cached)
+	}
+
+	reqTime := t.now()
+	resp, err := t.transport().RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	respTime := t.now()
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified &&
+		outReq != req {
+		return t.revalidated(req, key, e, cached, resp, reqTime, respTime)
+	}
+
+	if req.Method == http.MethodGet && !reqCC.has("no-store") &&
+		storable(resp) {
+		t.storeOnEOF(req, key, resp, reqTime, respTime)
+	}
+	resp.Header.Set(XCache, "MISS")
+
+	return resp, nil
+}
+
+// 저장된 응답 중 요청에 사용할 수 있는 것을 반환
+func (t *Transport) lookup(req *http.Request, key string) (*entry, *http.Response) {
+	b, ok := t.Store.Get(key)
+	if !ok {
+		return nil, nil
+	}
+
+	e, err := unmarshalEntry(b)
+	if err != nil || !e.matches(req) {
+		return nil, nil
+	}
+
+	resp, err := e.response(req)
+	if err != nil {
+		return nil, nil
+	}
+
+	return e, resp
+}
+
+// 저장된 응답을 서버에 확인하지 않고 사용할 수 있는지 여부
+// 요청의 max-age, min-fresh, max-stale 지시자로 호출자가 허용할 나이를 조정할 수 있음
+func (t *Transport) usable(reqCC, respCC directives, age,
+	lifetime time.Duration) bool {
+	// no-cache는 저장은 하되, 사용할 때마다 서버에 확인하라는 의미
+	if reqCC.has("no-cache") || respCC.has("no-cache") {
+		return false
+	}
+	if maxAge, ok := reqCC.seconds("max-age"); ok && age > maxAge {
+		return false
+	}
+	if minFresh, ok := reqCC.seconds("min-fresh"); ok && lifetime-age < minFresh {
+		return false
+	}
+	if age < lifetime {
+		return true
+	}
+
+	// 오래된 응답은 서버가 must-revalidate를 지정하지 않았고, 호출자가 max-stale로 허용한 경우에만 사용
+	if respCC.has("must-revalidate") || !reqCC.has("max-stale") {
+		return false
+	}
+	maxStale, ok := reqCC.seconds("max-stale")
+	if reqCC["max-stale"] == "" {
+		// 값이 없는 max-stale은 얼마나 오래되었든 허용
+		return true
+	}
+
+	return ok && age-lifetime <= maxStale
+}
+
+// 저장된 응답의 검증자(validator)로 조건부 요청을 생성
+func conditional(req *http.Request, cached *http.Response) *http.Request {
+	etag := cached.Header.Get("ETag")
+	lastModified := cached.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return req
+	}
+
+	r := req.Clone(req.Context())
+	if etag != "" {
+		r.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		r.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return r
+}
+
+// 304 응답의 헤더로 저장된 응답을 갱신하고, 저장된 응답을 반환 (RFC 9111 4.3.4)
+func (t *Transport) revalidated(req *http.Request, key string, e *entry,
+	cached, notModified *http.Response,
+	reqTime, respTime time.Time) (*http.Response, error) {
+	_, _ = io.Copy(io.Discard, notModified.Body)
+	_ = notModified.Body.Close()
+
+	for name, values := range notModified.Header {
+		switch name {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+			continue
+		}
+		cached.Header[name] = values
+	}
+	cached.Header.Del("Age")
+	cached.Header.Del(XCache)
+
+	if req.Method == http.MethodGet {
+		body, err := io.ReadAll(cached.Body)
+		_ = cached.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		t.store(req, key, cached, body, reqTime, respTime)
+		cached.Body = io.NopCloser(bytes.NewReader(body))
+	} else {
+		// HEAD 요청의 304 응답으로는 저장된 GET 응답의 시각만 갱신
+		e.RequestTime, e.ResponseTime = reqTime, respTime
+		if b, err := e.marshal(); err == nil {
+			t.Store.Set(key, b)
+		}
+	}
+
+	cached.Header.Set(XCache, "REVALIDATED")
+
+	return cached, nil
+}
+
+// 응답 body를 호출자가 끝까지 읽으면 저장
+// 호출자가 body를 다 읽지 않고 닫으면 저장하지 않음
+func (t *Transport) storeOnEOF(req *http.Request, key string,
+	resp *http.Response, reqTime, respTime time.Time) {
+	// 저장되는 응답에는 Request 필드가 포함되지 않으므로 복사본을 만들어 둠
+	stored := *resp
+	stored.Header = resp.Header.Clone()
+
+	resp.Body = &cachingBody{
+		ReadCloser: resp.Body,
+		limit:      t.maxBodySize(),
+		onEOF: func(body []byte) {
+			t.store(req, key, &stored, body, reqTime, respTime)
+		},
+	}
+}
+
+func (t *Transport) store(req *http.Request, key string, resp *http.Response,
+	body []byte, reqTime, respTime time.Time) {
+	e := &entry{RequestTime: reqTime, ResponseTime: respTime}
+
+	for _, field := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(field, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if e.Vary == nil {
+				e.Vary = make(map[string]string)
+			}
+			e.Vary[name] = req.Header.Get(name)
+		}
+	}
+
+	var err error
+	e.Response, err = dumpResponse(resp, body)
+	if err != nil {
+		return
+	}
+	b, err := e.marshal()
+	if err != nil {
+		return
+	}
+
+	t.Store.Set(key, b)
+}
+
+// 응답을 저장할 수 있는지 여부 (RFC 9111 3)
+func storable(resp *http.Response) bool {
+	if !heuristicallyCacheable[resp.StatusCode] {
+		return false
+	}
+
+	cc := parseCacheControl(resp.Header)
+	if cc.has("no-store") {
+		return false
+	}
+	// Vary: *는 요청 헤더 외의 요소로 응답이 달라진다는 의미이므로 재사용할 수 없음
+	for _, v := range resp.Header.Values("Vary") {
+		if strings.TrimSpace(v) == "*" {
+			return false
+		}
+	}
+
+	// 신선도 수명이 있거나, 나중에 검증할 수 있는 응답만 저장할 가치가 있음
+	return freshnessLifetime(resp, cc) > 0 || cc.has("no-cache") ||
+		resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+func safe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+func gatewayTimeout(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "504 Gateway Timeout",
+		StatusCode: http.StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{XCache: {"MISS"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+}
+
+// 읽은 내용을 버퍼에 모아 두었다가 EOF에 도달하면 onEOF 함수를 호출하는 응답 body
+type cachingBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	limit int64
+	onEOF func(body []byte)
+	done  bool
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if !b.done {
+		if int64(b.buf.Len()+n) > b.limit {
+			// 너무 큰 응답은 저장하지 않음
+			b.done = true
+			b.buf = bytes.Buffer{}
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF && !b.done {
+		b.done = true
+		b.onEOF(b.buf.Bytes())
+	}
+
+	return n, err
+}
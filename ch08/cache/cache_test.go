@@ -0,0 +1,335 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 수동으로 시간을 흐르게 하는 가짜 시계
+// 서버의 Date 헤더와 캐시가 같은 시계를 사용하므로, 실제 시간과 관계없이 결정적임
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+type testServer struct {
+	*httptest.Server
+	hits atomic.Int32
+}
+
+// 요청마다 hits를 증가시키고, 가짜 시계의 시각으로 Date 헤더를 설정하는 서버
+func newTestServer(t *testing.T, clock *fakeClock,
+	h func(w http.ResponseWriter, r *http.Request)) *testServer {
+	t.Helper()
+
+	ts := new(testServer)
+	ts.Server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ts.hits.Add(1)
+			w.Header().Set("Date", clock.Now().UTC().Format(http.TimeFormat))
+			h(w, r)
+		}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func newClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+// 요청을 보내고 body와 X-Cache 헤더를 반환
+func get(t *testing.T, c *http.Client, method, url string,
+	header http.Header) (string, string, int) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b), resp.Header.Get(XCache), resp.StatusCode
+}
+
+func expect(t *testing.T, body, xcache, expectedBody, expectedXCache string) {
+	t.Helper()
+
+	if body != expectedBody || xcache != expectedXCache {
+		t.Fatalf("expected %q (%s); actual %q (%s)", expectedBody,
+			expectedXCache, body, xcache)
+	}
+}
+
+func TestMaxAgeAndETag(t *testing.T) {
+	for name, store := range map[string]Store{
+		"memory": NewMemoryStore(0),
+		"disk":   DiskStore{Dir: t.TempDir()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clock := newClock()
+			ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				w.Header().Set("ETag", `"v1"`)
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = io.WriteString(w, "hello")
+			})
+			c := (&Transport{Store: store, Now: clock.Now}).Client()
+
+			body, xc, _ := get(t, c, http.MethodGet, ts.URL, nil)
+			expect(t, body, xc, "hello", "MISS")
+
+			// 60초 동안은 서버에 요청하지 않음
+			clock.Advance(30 * time.Second)
+			body, xc, _ = get(t, c, http.MethodGet, ts.URL, nil)
+			expect(t, body, xc, "hello", "HIT")
+			// HEAD 요청도 저장된 GET 응답으로 응답
+			body, xc, _ = get(t, c, http.MethodHead, ts.URL, nil)
+			expect(t, body, xc, "", "HIT")
+			if hits := ts.hits.Load(); hits != 1 {
+				t.Fatalf("expected 1 request to the server; actual %d", hits)
+			}
+
+			// 만료된 후에는 If-None-Match 헤더로 확인
+			clock.Advance(31 * time.Second)
+			body, xc, _ = get(t, c, http.MethodGet, ts.URL, nil)
+			expect(t, body, xc, "hello", "REVALIDATED")
+
+			// 304 응답으로 신선도가 갱신됨
+			body, xc, _ = get(t, c, http.MethodGet, ts.URL, nil)
+			expect(t, body, xc, "hello", "HIT")
+
+			// 요청의 no-cache 지시자는 서버에 확인하도록 강제함
+			body, xc, _ = get(t, c, http.MethodGet, ts.URL,
+				http.Header{"Cache-Control": {"no-cache"}})
+			expect(t, body, xc, "hello", "REVALIDATED")
+			if hits := ts.hits.Load(); hits != 3 {
+				t.Fatalf("expected 3 requests to the server; actual %d", hits)
+			}
+		})
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	clock := newClock()
+	modified := clock.Now().Add(-time.Hour)
+	version := "v1"
+
+	ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+		// 매번 서버에 확인하도록 지정
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		if ims, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil &&
+			!modified.After(ims) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = io.WriteString(w, version)
+	})
+	c := (&Transport{Store: NewMemoryStore(0), Now: clock.Now}).Client()
+
+	body, xc, _ := get(t, c, http.MethodGet, ts.URL, nil)
+	expect(t, body, xc, "v1", "MISS")
+	body, xc, _ = get(t, c, http.MethodGet, ts.URL, nil)
+	expect(t, body, xc, "v1", "REVALIDATED")
+
+	// 리소스가 바뀌면 새로운 응답을 받아 저장
+	modified, version = clock.Now(), "v2"
+	clock.Advance(time.Second)
+	body, xc, _ = get(t, c, http.MethodGet, ts.URL, nil)
+	expect(t, body, xc, "v2", "MISS")
+	body, xc, _ = get(t, c, http.MethodGet, ts.URL, nil)
+	expect(t, body, xc, "v2", "REVALIDATED")
+}
+
+func TestVary(t *testing.T) {
+	clock := newClock()
+	ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		_, _ = io.WriteString(w, r.Header.Get("Accept-Language"))
+	})
+	c := (&Transport{Store: NewMemoryStore(0), Now: clock.Now}).Client()
+
+	en := http.Header{"Accept-Language": {"en"}}
+	ko := http.Header{"Accept-Language": {"ko"}}
+
+	body, xc, _ := get(t, c, http.MethodGet, ts.URL, en)
+	expect(t, body, xc, "en", "MISS")
+	body, xc, _ = get(t, c, http.MethodGet, ts.URL, en)
+	expect(t, body, xc, "en", "HIT")
+	// 헤더 값이 다르면 저장된 응답을 사용하지 않음
+	body, xc, _ = get(t, c, http.MethodGet, ts.URL, ko)
+	expect(t, body, xc, "ko", "MISS")
+}
+
+func TestNotStored(t *testing.T) {
+	clock := newClock()
+	for _, c := range []struct {
+		name   string
+		header http.Header
+		status int
+	}{
+		{"no-store", http.Header{"Cache-Control": {"no-store, max-age=60"}}, 200},
+		{"vary star", http.Header{"Cache-Control": {"max-age=60"},
+			"Vary": {"*"}}, 200},
+		{"no freshness", http.Header{}, 200},
+		{"uncacheable status", http.Header{"Cache-Control": {"max-age=60"}}, 500},
+	} {
+		ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range c.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(c.status)
+		})
+		client := (&Transport{Store: NewMemoryStore(0), Now: clock.Now}).Client()
+
+		for i := 0; i < 2; i++ {
+			if _, xc, _ := get(t, client, http.MethodGet, ts.URL, nil); xc != "MISS" {
+				t.Errorf("%s: expected MISS; actual %s", c.name, xc)
+			}
+		}
+	}
+}
+
+func TestExpiresAndHeuristic(t *testing.T) {
+	clock := newClock()
+	ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+		now := clock.Now()
+		switch r.URL.Path {
+		case "/expires":
+			w.Header().Set("Expires", now.Add(time.Minute).Format(http.TimeFormat))
+		case "/heuristic":
+			// 10시간 전에 수정되었으므로 1시간 동안 신선함
+			w.Header().Set("Last-Modified",
+				now.Add(-10*time.Hour).Format(http.TimeFormat))
+		}
+	})
+	c := (&Transport{Store: NewMemoryStore(0), Now: clock.Now}).Client()
+
+	for path, lifetime := range map[string]time.Duration{
+		"/expires":   time.Minute,
+		"/heuristic": time.Hour,
+	} {
+		_, _, _ = get(t, c, http.MethodGet, ts.URL+path, nil)
+
+		clock.Advance(lifetime - time.Second)
+		if _, xc, _ := get(t, c, http.MethodGet, ts.URL+path, nil); xc != "HIT" {
+			t.Errorf("%s: expected HIT before expiry; actual %s", path, xc)
+		}
+
+		clock.Advance(2 * time.Second)
+		if _, xc, _ := get(t, c, http.MethodGet, ts.URL+path, nil); xc == "HIT" {
+			t.Errorf("%s: expected a stale response after expiry", path)
+		}
+	}
+}
+
+func TestRequestDirectives(t *testing.T) {
+	clock := newClock()
+	ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = io.WriteString(w, "hello")
+	})
+	c := (&Transport{Store: NewMemoryStore(0), Now: clock.Now}).Client()
+
+	// 저장된 응답이 없으면 only-if-cached 요청은 504
+	onlyCached := http.Header{"Cache-Control": {"only-if-cached"}}
+	if _, _, code := get(t, c, http.MethodGet, ts.URL, onlyCached); code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d; actual %d", http.StatusGatewayTimeout, code)
+	}
+
+	_, _, _ = get(t, c, http.MethodGet, ts.URL, nil)
+	clock.Advance(30 * time.Second)
+
+	// 서버에서 새로운 응답을 받으면 나이가 0이 되므로, MISS 후에는 다시 시간을 흐르게 함
+	for _, d := range []struct {
+		cc     string
+		xcache string
+	}{
+		{"max-age=40", "HIT"},
+		{"min-fresh=50", "MISS"}, // 남은 신선도가 부족함
+		{"max-age=10", "MISS"},   // 허용할 최대 나이보다 오래됨
+	} {
+		_, xc, _ := get(t, c, http.MethodGet, ts.URL,
+			http.Header{"Cache-Control": {d.cc}})
+		if xc != d.xcache {
+			t.Errorf("%s: expected %s; actual %s", d.cc, d.xcache, xc)
+		}
+		if xc == "MISS" {
+			clock.Advance(30 * time.Second)
+		}
+	}
+
+	// 만료된 응답도 max-stale로 허용하면 사용
+	clock.Advance(90 * time.Second)
+	if _, xc, _ := get(t, c, http.MethodGet, ts.URL,
+		http.Header{"Cache-Control": {"max-stale=120"}}); xc != "HIT" {
+		t.Errorf("max-stale: expected HIT; actual %s", xc)
+	}
+}
+
+func TestUnsafeMethodInvalidates(t *testing.T) {
+	clock := newClock()
+	ts := newTestServer(t, clock, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+	})
+	c := (&Transport{Store: NewMemoryStore(0), Now: clock.Now}).Client()
+
+	_, _, _ = get(t, c, http.MethodGet, ts.URL, nil)
+	if _, xc, _ := get(t, c, http.MethodGet, ts.URL, nil); xc != "HIT" {
+		t.Fatalf("expected HIT; actual %s", xc)
+	}
+
+	_, _, _ = get(t, c, http.MethodPost, ts.URL, nil)
+	if _, xc, _ := get(t, c, http.MethodGet, ts.URL, nil); xc != "MISS" {
+		t.Fatalf("expected MISS after POST; actual %s", xc)
+	}
+}
+
+func TestMemoryStoreMaxEntries(t *testing.T) {
+	s := NewMemoryStore(2)
+	for _, k := range []string{"a", "b", "c"} {
+		s.Set(k, []byte(k))
+	}
+
+	if len(s.entries) != 2 {
+		t.Fatalf("expected 2 entries; actual %d", len(s.entries))
+	}
+	if v, ok := s.Get("c"); !ok || string(v) != "c" {
+		t.Fatal("most recent entry evicted")
+	}
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache-Control 헤더의 지시자
+// 값이 없는 지시자(no-cache 등)는 빈 문자열을 값으로 가짐
+type directives map[string]string
+
+func parseCacheControl(h http.Header) directives {
+	d := make(directives)
+
+	for _, line := range h.Values("Cache-Control") {
+		for _, part := range strings.Split(line, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(part, "=")
+			d[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(
+				strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return d
+}
+
+func (d directives) has(name string) bool {
+	_, ok := d[name]
+	return ok
+}
+
+// 초 단위 값을 가진 지시자(max-age 등)의 값
+func (d directives) seconds(name string) (time.Duration, bool) {
+	v, ok := d[name]
+	if !ok {
+		return 0, false
+	}
+
+	s, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || s < 0 {
+		// 잘못된 값은 이미 만료된 것으로 취급 (RFC 9111 4.2.1)
+		return 0, true
+	}
+
+	return time.Duration(s) * time.Second, true
+}
+
+// 명시적인 신선도 정보가 없어도 휴리스틱으로 캐시할 수 있는 상태 코드 (RFC 9110 15.1)
+var heuristicallyCacheable = map[int]bool{
+	200: true, 203: true, 204: true, 206: true, 300: true, 301: true,
+	308: true, 404: true, 405: true, 410: true, 414: true, 501: true,
+}
+
+// 응답의 신선도 수명 (RFC 9111 4.2.1)
+// max-age, Expires 순서로 확인하고, 둘 다 없으면 Last-Modified로부터 경과한 시간의 10%를 사용
+func freshnessLifetime(resp *http.Response, cc directives) time.Duration {
+	if d, ok := cc.seconds("max-age"); ok {
+		return d
+	}
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		date = time.Time{}
+	}
+
+	if v := resp.Header.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		// 잘못된 Expires 값(예: "0")은 이미 만료된 것으로 취급
+		if err != nil || date.IsZero() {
+			return 0
+		}
+		return max(expires.Sub(date), 0)
+	}
+
+	if !heuristicallyCacheable[resp.StatusCode] || date.IsZero() {
+		return 0
+	}
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil &&
+		lm.Before(date) {
+		return date.Sub(lm) / 10
+	}
+
+	return 0
+}
+
+// 저장된 응답의 현재 나이 (RFC 9111 4.2.3)
+func currentAge(resp *http.Response, e *entry, now time.Time) time.Duration {
+	var ageValue time.Duration
+	if s, err := strconv.ParseInt(resp.Header.Get("Age"), 10, 64); err == nil &&
+		s > 0 {
+		ageValue = time.Duration(s) * time.Second
+	}
+
+	var apparentAge time.Duration
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		apparentAge = max(e.ResponseTime.Sub(date), 0)
+	}
+
+	responseDelay := e.ResponseTime.Sub(e.RequestTime)
+	correctedInitialAge := max(apparentAge, ageValue+responseDelay)
+	residentTime := now.Sub(e.ResponseTime)
+
+	return correctedInitialAge + residentTime
+}
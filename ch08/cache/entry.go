@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+// 저장소에 저장되는 캐시 항목
+type entry struct {
+	RequestTime  time.Time         // 요청을 보낸 시각
+	ResponseTime time.Time         // 응답을 받은 시각
+	Vary         map[string]string // 응답의 Vary 헤더에 나열된 요청 헤더들의 값
+	Response     []byte            // httputil.DumpResponse로 직렬화한 응답
+}
+
+func (e *entry) marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func unmarshalEntry(b []byte) (*entry, error) {
+	var e entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// 저장된 응답을 복원
+func (e *entry) response(req *http.Request) (*http.Response, error) {
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(e.Response)), req)
+}
+
+// 요청이 저장된 응답의 Vary 헤더 조건과 일치하는지 확인
+// 예) Vary: Accept-Encoding인 응답은 Accept-Encoding 헤더 값이 같은 요청에만 사용할 수 있음
+func (e *entry) matches(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 응답 헤더와 body를 직렬화
+func dumpResponse(resp *http.Response, body []byte) ([]byte, error) {
+	r := *resp
+	r.Body = nopCloser{bytes.NewReader(body)}
+	r.ContentLength = int64(len(body))
+	r.TransferEncoding = nil
+	r.Header = resp.Header.Clone()
+	r.Header.Del("Transfer-Encoding")
+
+	return httputil.DumpResponse(&r, true)
+}
+
+type nopCloser struct{ *bytes.Reader }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 캐시 항목을 저장하는 저장소
+// 키는 요청 URL이며, 값은 직렬화된 캐시 항목
+// 여러 고루틴에서 동시에 호출할 수 있어야 함
+type Store interface {
+	// 키에 해당하는 값을 반환. 없으면 ok는 false
+	Get(key string) (value []byte, ok bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
+
+// 메모리에 저장하는 저장소
+// 최대 항목 수를 넘으면 임의의 항목을 지워 메모리 사용량을 제한함
+type MemoryStore struct {
+	MaxEntries int // 0 이하면 제한 없음
+
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{MaxEntries: maxEntries, entries: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.entries[key]
+
+	return v, ok
+}
+
+func (s *MemoryStore) Set(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries == nil {
+		s.entries = make(map[string][]byte)
+	}
+	if _, ok := s.entries[key]; !ok && s.MaxEntries > 0 {
+		// 맵의 순회 순서는 임의적이므로, 첫 번째 항목을 지우면 임의의 항목을 지우는 셈
+		for k := range s.entries {
+			if len(s.entries) < s.MaxEntries {
+				break
+			}
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = value
+}
+
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+}
+
+// 디렉터리에 항목마다 파일 하나로 저장하는 저장소
+// 파일 이름은 키의 SHA-256 해시이므로, URL에 파일 시스템에서 사용할 수 없는 문자가 있어도 문제없음
+// 프로세스를 재시작해도 캐시가 유지됨
+type DiskStore struct {
+	Dir string
+}
+
+func (s DiskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:]))
+}
+
+func (s DiskStore) Get(key string) ([]byte, bool) {
+	b, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	return b, true
+}
+
+// 임시 파일에 쓴 후 이름을 바꿔, 읽는 쪽이 일부만 쓰인 파일을 보지 않게 함
+// 저장에 실패하면 캐시하지 않은 것과 같으므로 에러는 무시
+func (s DiskStore) Set(key string, value []byte) {
+	f, err := os.CreateTemp(s.Dir, ".tmp-")
+	if err != nil {
+		return
+	}
+
+	_, err = f.Write(value)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+func (s DiskStore) Delete(key string) {
+	_ = os.Remove(s.path(key))
+}
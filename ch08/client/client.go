@@ -0,0 +1,356 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Client는 http.Client를 감싸 요청별 타임아웃, 멱등 메서드의 재시도, 응답 body 정리,
+// JSON 요청/응답 처리와 상태 코드의 에러 변환을 담당
+type Client struct {
+	// 실제 요청을 보낼 클라이언트. nil이면 http.DefaultClient를 사용
+	HTTPClient *http.Client
+
+	// 상대 경로로 요청할 때 기준이 되는 URL. 예) https://api.example.com/v1/
+	BaseURL *url.URL
+
+	// 모든 요청에 추가할 헤더
+	Header http.Header
+
+	// 시도 한 번에 대한 타임아웃. 0이면 요청 콘텍스트의 데드라인만 적용
+	// 응답 body를 읽는 시간도 포함되며, 재시도할 때마다 새로 적용됨
+	Timeout time.Duration
+
+	// 첫 번째 시도 이후 최대 재시도 횟수
+	Retries int
+
+	// n번째 재시도 전 대기 시간을 반환. nil이면 DefaultBackoff를 사용
+	// 서버가 Retry-After 헤더를 보내면, 둘 중 긴 시간만큼 대기
+	Backoff func(n int) time.Duration
+}
+
+// 기본 재시도 대기 시간
+// 100ms에서 시작해 재시도마다 두 배씩 늘리되 최대 5초를 넘지 않으며,
+// 여러 클라이언트가 동시에 재시도하지 않도록 0부터 그 값 사이의 임의의 시간을 반환 (full jitter)
+func DefaultBackoff(n int) time.Duration {
+	d := 5 * time.Second
+	if n < 6 {
+		d = min(100*time.Millisecond<<n, d)
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func New(baseURL string) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{BaseURL: u, Retries: 2}, nil
+}
+
+// 요청을 보내고 응답을 반환
+// 멱등 메서드(GET, HEAD, OPTIONS, TRACE, PUT, DELETE)이거나 Idempotency-Key 헤더가 있는 요청은
+// 네트워크 에러나 429, 502, 503, 504 응답을 받으면 재시도함
+// body가 있는 요청을 재시도하려면 req.GetBody가 설정되어 있어야 함 (http.NewRequest는 bytes.Buffer,
+// bytes.Reader, strings.Reader에 대해 자동으로 설정)
+// 호출자는 반드시 응답 body를 닫아야 하며, 상태 코드가 2xx가 아니어도 에러를 반환하지 않음
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	for n := 0; ; n++ {
+		resp, err := c.attempt(req)
+
+		if n >= c.Retries || !retryable(req, resp, err) || !rewindable(req) {
+			return resp, err
+		}
+
+		wait := c.backoff(n)
+		if resp != nil {
+			if ra := retryAfter(resp.Header.Get("Retry-After")); ra > wait {
+				wait = ra
+			}
+			// 연결을 재사용할 수 있도록 body를 모두 읽고 닫음
+			drain(resp.Body)
+		}
+
+		if req.GetBody != nil {
+			body, gErr := req.GetBody()
+			if gErr != nil {
+				return nil, gErr
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// 타임아웃을 적용해 요청을 한 번 보냄
+func (c *Client) attempt(req *http.Request) (*http.Response, error) {
+	if c.Timeout <= 0 {
+		return c.httpClient().Do(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.Timeout)
+	resp, err := c.httpClient().Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// body를 다 읽기 전에 콘텍스트를 취소하면 읽기가 실패하므로, body를 닫을 때 취소함
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	return http.DefaultClient
+}
+
+func (c *Client) backoff(n int) time.Duration {
+	if c.Backoff != nil {
+		return c.Backoff(n)
+	}
+
+	return DefaultBackoff(n)
+}
+
+// 기준 URL에 대한 상대 경로로 새로운 요청을 생성하고, 클라이언트의 기본 헤더를 추가
+func (c *Client) NewRequest(ctx context.Context, method, path string,
+	body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	if c.BaseURL != nil {
+		u = c.BaseURL.ResolveReference(u)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	return req, nil
+}
+
+// in을 JSON으로 인코딩해 요청 body로 보내고, 응답 body를 out으로 디코딩
+// in이 nil이면 body 없이, out이 nil이면 응답 body를 버림
+// 상태 코드가 2xx가 아니면 *StatusError를 반환하며, 어떤 경우든 응답 body는 닫힘
+func (c *Client) DoJSON(ctx context.Context, method, path string,
+	in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := c.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if out != nil {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer drain(resp.Body)
+
+	if err = CheckResponse(resp); err != nil {
+		return err
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent ||
+		req.Method == http.MethodHead {
+		return nil
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) GetJSON(ctx context.Context, path string, out any) error {
+	return c.DoJSON(ctx, http.MethodGet, path, nil, out)
+}
+
+func (c *Client) PostJSON(ctx context.Context, path string, in, out any) error {
+	return c.DoJSON(ctx, http.MethodPost, path, in, out)
+}
+
+func (c *Client) PutJSON(ctx context.Context, path string, in, out any) error {
+	return c.DoJSON(ctx, http.MethodPut, path, in, out)
+}
+
+func (c *Client) Delete(ctx context.Context, path string) error {
+	return c.DoJSON(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// 요청을 재시도해도 되는지 여부
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+
+	if err != nil {
+		// 호출자가 요청을 취소했거나 데드라인이 지났다면 재시도하지 않음
+		// 시도 한 번의 타임아웃(Client.Timeout)은 요청 콘텍스트의 에러가 아니므로 재시도함
+		return req.Context().Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// 요청 body를 처음부터 다시 보낼 수 있는지 여부
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// Retry-After 헤더 값(초 또는 HTTP 날짜)을 대기 시간으로 변환
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// body를 끝까지 읽고 닫아, 전송 계층이 연결을 재사용할 수 있게 함
+// 너무 큰 body는 연결을 재사용하는 것보다 닫는 편이 나으므로 일정 크기까지만 읽음
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 1<<16))
+	_ = body.Close()
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
+
+// 상태 코드별 에러. errors.Is 함수로 *StatusError와 비교할 수 있음
+var (
+	ErrBadRequest      = errors.New("bad request")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrTooManyRequests = errors.New("too many requests")
+	ErrServer          = errors.New("server error")
+)
+
+// 상태 코드가 2xx가 아닌 응답
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       []byte // 응답 body의 앞부분. 서버가 보낸 에러 메시지를 확인할 때 사용
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode,
+		http.StatusText(e.StatusCode))
+	if len(e.Body) > 0 {
+		msg += ": " + string(bytes.TrimSpace(e.Body))
+	}
+
+	return msg
+}
+
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrTooManyRequests
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+
+	return nil
+}
+
+// 상태 코드가 2xx가 아니면 응답 body의 앞부분을 포함한 *StatusError를 반환
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	return &StatusError{
+		Method:     resp.Request.Method,
+		URL:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Body:       b,
+	}
+}
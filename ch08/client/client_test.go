@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type User struct {
+	First string
+	Last  string
+}
+
+func newTestClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+
+	c, err := New(ts.URL + "/v1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 테스트에서는 대기하지 않음
+	c.Backoff = func(int) time.Duration { return 0 }
+
+	return c
+}
+
+func TestJSON(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/users" {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Header.Get("X-Test") != "yes" {
+				http.Error(w, "missing header", http.StatusBadRequest)
+				return
+			}
+
+			var u User
+			if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			u.First = strings.ToUpper(u.First)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(u)
+		}))
+	c.Header = http.Header{"X-Test": {"yes"}}
+
+	var u User
+	err := c.PostJSON(context.Background(), "users",
+		User{First: "Adam", Last: "Woodbeck"}, &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.First != "ADAM" || u.Last != "Woodbeck" {
+		t.Fatalf("unexpected response %+v", u)
+	}
+
+	// 상태 코드는 에러로 변환되어야 함
+	err = c.GetJSON(context.Background(), "missing", &u)
+	var sErr *StatusError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &sErr) {
+		t.Fatalf("expected ErrNotFound; actual %v", err)
+	}
+	if sErr.StatusCode != http.StatusNotFound ||
+		!strings.Contains(string(sErr.Body), "404 page not found") {
+		t.Fatalf("unexpected status error %q", sErr)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			// 처음 두 번은 일시적인 에러를 반환
+			if attempts.Add(1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				http.Error(w, "try again", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write(b)
+		}))
+
+	// 재시도할 때마다 같은 body를 다시 보내야 함
+	var out string
+	err := c.PutJSON(context.Background(), "echo", "ping", &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "ping" || attempts.Load() != 3 {
+		t.Fatalf("unexpected result %q after %d attempts", out, attempts.Load())
+	}
+
+	// POST는 멱등이 아니므로 재시도하지 않음
+	attempts.Store(0)
+	err = c.PostJSON(context.Background(), "echo", "ping", &out)
+	if !errors.Is(err, ErrServer) || attempts.Load() != 1 {
+		t.Fatalf("expected one failed attempt; actual %d: %v",
+			attempts.Load(), err)
+	}
+
+	// Idempotency-Key 헤더가 있는 POST는 재시도함
+	attempts.Store(0)
+	req, err := c.NewRequest(context.Background(), http.MethodPost, "echo",
+		strings.NewReader(`"pong"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Idempotency-Key", "42")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"pong"` || attempts.Load() != 3 {
+		t.Fatalf("unexpected result %q after %d attempts", b, attempts.Load())
+	}
+
+	// 재시도 횟수를 모두 사용하면 마지막 응답을 에러로 반환
+	attempts.Store(-10)
+	err = c.GetJSON(context.Background(), "echo", &out)
+	if !errors.Is(err, ErrServer) || attempts.Load() != -7 {
+		t.Fatalf("expected 3 failed attempts; actual %d: %v",
+			attempts.Load()+10, err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// 첫 번째 시도는 타임아웃보다 오래 걸림
+			if attempts.Add(1) == 1 {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+				return
+			}
+			_, _ = io.WriteString(w, `"ok"`)
+		}))
+	c.Timeout = 100 * time.Millisecond
+
+	// 시도 한 번의 타임아웃은 재시도함
+	var out string
+	if err := c.GetJSON(context.Background(), "slow", &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != "ok" || attempts.Load() != 2 {
+		t.Fatalf("unexpected result %q after %d attempts", out, attempts.Load())
+	}
+
+	// 요청 콘텍스트의 데드라인이 지나면 재시도하지 않음
+	attempts.Store(0)
+	c.Timeout = 0
+	ctx, cancel := context.WithTimeout(context.Background(),
+		100*time.Millisecond)
+	defer cancel()
+	err := c.GetJSON(ctx, "slow", &out)
+	if !errors.Is(err, context.DeadlineExceeded) || attempts.Load() != 1 {
+		t.Fatalf("expected deadline exceeded after 1 attempt; actual %d: %v",
+			attempts.Load(), err)
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	for n, limit := range []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+	} {
+		if d := DefaultBackoff(n); d < 0 || d > limit {
+			t.Errorf("backoff %d: %s exceeds %s", n, d, limit)
+		}
+	}
+	if d := DefaultBackoff(100); d > 5*time.Second {
+		t.Errorf("backoff exceeds the maximum: %s", d)
+	}
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 멀티파트 요청 body의 파트 하나
+// FileName이 비어 있으면 일반 폼 필드, 그렇지 않으면 첨부 파일로 전송
+type Part struct {
+	FieldName   string
+	FileName    string
+	ContentType string    // 비어 있으면 첨부 파일은 확장자로 추측하고, 폼 필드는 생략
+	Body        io.Reader // io.Closer를 구현하면 전송 후 닫음
+	Size        int64     // 진행 상황 보고에 사용할 크기. 모르면 0
+}
+
+// 문자열 값을 가진 폼 필드 파트
+func FieldPart(name, value string) Part {
+	return Part{FieldName: name, Body: strings.NewReader(value),
+		Size: int64(len(value))}
+}
+
+// 파일을 첨부하는 파트
+// 파일은 요청 body를 쓸 때 읽으며, 다 읽은 후 닫힘
+func FilePart(fieldName, path string) (Part, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Part{}, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return Part{}, err
+	}
+
+	return Part{
+		FieldName: fieldName,
+		FileName:  filepath.Base(path),
+		Body:      f,
+		Size:      fi.Size(),
+	}, nil
+}
+
+// 멀티파트 요청 body를 쓰는 동안 보고되는 진행 상황
+type Progress struct {
+	Part      string // 현재 파트의 파일 이름 또는 필드 이름
+	PartBytes int64  // 현재 파트에서 쓴 바이트 수
+	PartSize  int64  // 현재 파트의 크기. 모르면 0
+	Bytes     int64  // 모든 파트에서 쓴 바이트 수 (멀티파트 헤더와 바운더리 제외)
+	Total     int64  // 모든 파트의 크기 합. 크기를 모르는 파트가 있으면 0
+}
+
+// 파트들을 멀티파트 형식으로 스트리밍하는 요청 body와 Content-Type 헤더 값을 반환
+// post_test.go의 TestMultipartPost와 달리 전체 body를 메모리에 버퍼링하지 않고,
+// io.Pipe를 통해 HTTP 클라이언트가 body를 읽는 만큼만 파트를 읽어서 씀
+// progress가 nil이 아니면, 파트의 데이터를 쓸 때마다 호출됨
+// 반환된 body는 한 번만 읽을 수 있으므로, 요청을 재시도할 수 없음
+func NewMultipartBody(parts []Part,
+	progress func(Progress)) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		err := writeParts(mw, parts, progress)
+		if err == nil {
+			err = mw.Close()
+		}
+		// 에러가 발생하면 body를 읽는 쪽(HTTP 클라이언트)이 에러를 받아 요청이 실패함
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, mw.FormDataContentType()
+}
+
+func writeParts(mw *multipart.Writer, parts []Part,
+	progress func(Progress)) error {
+	// 쓰기가 중간에 실패하더라도 모든 파트의 body를 닫음
+	defer func() {
+		for _, p := range parts {
+			if c, ok := p.Body.(io.Closer); ok {
+				_ = c.Close()
+			}
+		}
+	}()
+
+	var total int64
+	for _, p := range parts {
+		if p.Size <= 0 {
+			total = 0
+			break
+		}
+		total += p.Size
+	}
+
+	pw := &progressWriter{report: progress, p: Progress{Total: total}}
+	for _, p := range parts {
+		w, err := mw.CreatePart(partHeader(p))
+		if err != nil {
+			return err
+		}
+
+		pw.w = w
+		pw.p.Part, pw.p.PartBytes, pw.p.PartSize = p.FileName, 0, p.Size
+		if pw.p.Part == "" {
+			pw.p.Part = p.FieldName
+		}
+
+		if _, err = io.Copy(pw, p.Body); err != nil {
+			return fmt.Errorf("writing part %q: %w", pw.p.Part, err)
+		}
+	}
+
+	return nil
+}
+
+// 파트의 Content-Disposition, Content-Type 헤더를 생성
+// multipart.Writer의 CreateFormFile 메서드는 Content-Type을 항상 application/octet-stream으로 설정하므로 직접 생성
+func partHeader(p Part) textproto.MIMEHeader {
+	h := make(textproto.MIMEHeader)
+
+	params := map[string]string{"name": p.FieldName}
+	if p.FileName != "" {
+		params["filename"] = p.FileName
+	}
+	h.Set("Content-Disposition", mime.FormatMediaType("form-data", params))
+
+	ct := p.ContentType
+	if ct == "" && p.FileName != "" {
+		ct = mime.TypeByExtension(filepath.Ext(p.FileName))
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+	}
+	if ct != "" {
+		h.Set("Content-Type", ct)
+	}
+
+	return h
+}
+
+type progressWriter struct {
+	w      io.Writer
+	p      Progress
+	report func(Progress)
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.p.PartBytes += int64(n)
+	pw.p.Bytes += int64(n)
+	if pw.report != nil && n > 0 {
+		pw.report(pw.p)
+	}
+
+	return n, err
+}
+
+// 파트들을 멀티파트 요청 body로 스트리밍해 POST 요청을 보냄
+// body를 다시 읽을 수 없으므로 재시도하지 않으며, 호출자는 반드시 응답 body를 닫아야 함
+func (c *Client) Upload(ctx context.Context, path string, parts []Part,
+	progress func(Progress)) (*http.Response, error) {
+	body, contentType := NewMultipartBody(parts, progress)
+
+	req, err := c.NewRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return c.Do(req)
+}
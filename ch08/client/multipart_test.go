@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/awoodbeck/gnp/ch09/handlers"
+)
+
+func TestUpload(t *testing.T) {
+	dir := t.TempDir()
+	ts := httptest.NewServer(handlers.Upload(dir, 0))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := []Part{FieldPart("description", "Streamed files")}
+	for _, file := range []string{"../files/hello.txt", "../files/goodbye.txt"} {
+		p, err := FilePart("file", file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, p)
+	}
+	// 크기를 모르는 파트와 직접 지정한 Content-Type
+	big := strings.Repeat("x", 100000)
+	parts = append(parts, Part{FieldName: "data", FileName: "data.json",
+		ContentType: "application/x-test", Body: strings.NewReader(big)})
+
+	var reports []Progress
+	resp, err := c.Upload(context.Background(), "/upload", parts,
+		func(p Progress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatalf("unexpected status %q: %s", resp.Status, b)
+	}
+
+	var result handlers.UploadResult
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Fields["description"] != "Streamed files" ||
+		len(result.Files) != 3 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	for i, ct := range []string{"text/plain; charset=utf-8",
+		"text/plain; charset=utf-8", "application/x-test"} {
+		if result.Files[i].ContentType != ct {
+			t.Errorf("%s: expected content type %q; actual %q",
+				result.Files[i].FileName, ct, result.Files[i].ContentType)
+		}
+	}
+
+	for _, file := range []string{"hello.txt", "goodbye.txt"} {
+		expected, err := os.ReadFile(filepath.Join("..", "files", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(expected) != string(actual) {
+			t.Errorf("%s: expected %q; actual %q", file, expected, actual)
+		}
+	}
+
+	// 진행 상황은 모든 파트의 데이터를 합한 만큼 보고되어야 함
+	// 크기를 모르는 파트가 있으므로 전체 크기는 0
+	last := reports[len(reports)-1]
+	if last.Part != "data.json" || last.PartBytes != int64(len(big)) ||
+		last.Total != 0 {
+		t.Fatalf("unexpected final progress %+v", last)
+	}
+	var sum int64
+	for _, p := range parts {
+		sum += p.Size
+	}
+	if last.Bytes != sum+int64(len(big)) {
+		t.Fatalf("expected %d bytes; actual %d", sum+int64(len(big)), last.Bytes)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("disk failure") }
+
+func TestUploadPartError(t *testing.T) {
+	ts := httptest.NewServer(handlers.Upload(t.TempDir(), 0))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 파트를 읽다 실패하면 요청도 실패해야 함
+	_, err = c.Upload(context.Background(), "/upload",
+		[]Part{{FieldName: "file", FileName: "f.bin", Body: failingReader{}}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "disk failure") {
+		t.Fatalf("expected part error; actual %v", err)
+	}
+}
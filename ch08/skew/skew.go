@@ -0,0 +1,195 @@
+package skew
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// 응답을 받은 서버가 하나도 없을 때 반환
+	ErrNoSamples = errors.New("no successful samples")
+	// 모든 샘플이 이상치로 판단되어 합의 값을 계산할 수 없을 때 반환
+	ErrNoConsensus = errors.New("no samples within threshold of the median")
+)
+
+// Date 헤더는 초 단위까지만 표현하므로, 서버의 실제 시각은 헤더 값에서 최대 1초 이후일 수 있음
+// 헤더 값에 그 절반을 더해 오차의 기댓값을 0으로 만듦
+const dateResolution = time.Second
+
+// 서버 하나에 대한 측정 결과
+type Sample struct {
+	URL    string
+	Server time.Time     // 서버의 Date 헤더 값
+	RTT    time.Duration // 요청을 보낸 후 응답 헤더를 받을 때까지의 왕복 시간
+	Offset time.Duration // 서버 시각 - 로컬 시각. 양수면 로컬 시계가 느림
+	Err    error
+
+	Outlier bool // 합의 값 계산에서 제외되었는지 여부
+}
+
+// 여러 서버에 대한 측정 결과와 합의된 시각 차이
+type Result struct {
+	Offset  time.Duration // 이상치를 제외한 샘플들의 오프셋 중앙값
+	Spread  time.Duration // 이상치를 제외한 샘플들의 오프셋 최댓값과 최솟값의 차이
+	Samples []Sample      // 요청한 URL 순서대로의 측정 결과
+}
+
+// Estimator는 HTTP 응답의 Date 헤더로 로컬 시계와 서버 시계의 차이를 추정
+// 응답을 생성한 시점은 요청을 보낸 시점과 응답을 받은 시점 사이 어딘가이므로,
+// 그 중간 시점(왕복 시간의 절반)의 로컬 시각과 서버 시각을 비교함
+type Estimator struct {
+	// 요청을 보낼 클라이언트. nil이면 http.DefaultClient를 사용
+	Client *http.Client
+
+	// 서버 하나에 대한 타임아웃. 0이면 콘텍스트의 데드라인만 적용
+	Timeout time.Duration
+
+	// 로컬 시계. nil이면 time.Now를 사용. 테스트에서 가짜 시계를 주입할 때 사용
+	Now func() time.Time
+
+	// 오프셋이 중앙값에서 이 값보다 멀면 이상치로 판단
+	// 0이면 중앙값 절대 편차(MAD)의 3배와 Date 헤더 해상도(1초) 중 큰 값을 사용
+	Threshold time.Duration
+}
+
+func (e *Estimator) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+
+	return time.Now()
+}
+
+// HEAD 요청을 보내 서버 하나의 시각 차이를 측정
+func (e *Estimator) Measure(ctx context.Context, url string) Sample {
+	s := Sample{URL: url}
+
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	// 캐시나 프록시가 저장해 둔 응답의 Date 헤더는 과거 시각이므로 캐시를 사용하지 않도록 요청
+	req.Header.Set("Cache-Control", "no-cache")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	start := e.now()
+	resp, err := client.Do(req)
+	end := e.now()
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	_ = resp.Body.Close()
+
+	date := resp.Header.Get("Date")
+	if date == "" {
+		s.Err = fmt.Errorf("no Date header received from %s", url)
+		return s
+	}
+	s.Server, err = http.ParseTime(date)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+
+	s.RTT = end.Sub(start)
+	mid := start.Add(s.RTT / 2)
+	s.Offset = s.Server.Add(dateResolution / 2).Sub(mid)
+
+	return s
+}
+
+// 모든 URL에 동시에 요청을 보내 시각 차이를 측정하고, 이상치를 제외한 합의 값을 반환
+// 성공한 측정이 하나도 없으면 ErrNoSamples를, 모든 측정이 이상치이면 ErrNoConsensus를 반환
+func (e *Estimator) Estimate(ctx context.Context, urls []string) (Result, error) {
+	r := Result{Samples: make([]Sample, len(urls))}
+
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			r.Samples[i] = e.Measure(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	var offsets []time.Duration
+	for _, s := range r.Samples {
+		if s.Err == nil {
+			offsets = append(offsets, s.Offset)
+		}
+	}
+	if len(offsets) == 0 {
+		return r, ErrNoSamples
+	}
+
+	med := median(offsets)
+	threshold := e.Threshold
+	if threshold <= 0 {
+		// 중앙값 절대 편차: 이상치의 영향을 거의 받지 않는 산포도
+		deviations := make([]time.Duration, len(offsets))
+		for i, o := range offsets {
+			deviations[i] = abs(o - med)
+		}
+		threshold = max(3*median(deviations), dateResolution)
+	}
+
+	var kept []time.Duration
+	for i := range r.Samples {
+		s := &r.Samples[i]
+		if s.Err != nil {
+			continue
+		}
+		if abs(s.Offset-med) > threshold {
+			s.Outlier = true
+			continue
+		}
+		kept = append(kept, s.Offset)
+	}
+	if len(kept) == 0 {
+		// 샘플이 짝수 개이면 중앙값이 두 샘플의 평균이므로, 임곗값이 작으면 모두 제외될 수 있음
+		return r, fmt.Errorf("threshold %s: %w", threshold, ErrNoConsensus)
+	}
+
+	r.Offset = median(kept)
+	sort.Slice(kept, func(i, j int) bool { return kept[i] < kept[j] })
+	r.Spread = kept[len(kept)-1] - kept[0]
+
+	return r, nil
+}
+
+func median(d []time.Duration) time.Duration {
+	s := append([]time.Duration(nil), d...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+
+	if n := len(s); n%2 == 0 {
+		return (s[n/2-1] + s[n/2]) / 2
+	}
+
+	return s[len(s)/2]
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
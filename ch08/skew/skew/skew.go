@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/awoodbeck/gnp/ch08/skew"
+)
+
+var (
+	timeout = flag.Duration("timeout", 5*time.Second, "per-server timeout")
+	servers = []string{
+		"https://www.time.gov/",
+		"https://www.google.com/",
+		"https://www.cloudflare.com/",
+		"https://www.apple.com/",
+		"https://www.microsoft.com/",
+	}
+)
+
+func init() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(),
+			"Usage:\n\t%s [flags] [URL...]\n\nDefault URLs: %v\n",
+			filepath.Base(os.Args[0]), servers)
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() > 0 {
+		servers = flag.Args()
+	}
+
+	e := &skew.Estimator{Timeout: *timeout}
+	r, err := e.Estimate(context.Background(), servers)
+
+	// 서버별 측정 결과
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "URL\tRTT\tOFFSET\t")
+	for _, s := range r.Samples {
+		switch {
+		case s.Err != nil:
+			_, _ = fmt.Fprintf(tw, "%s\t-\t-\t%v\n", s.URL, s.Err)
+		case s.Outlier:
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\toutlier\n", s.URL,
+				s.RTT.Round(time.Millisecond), s.Offset.Round(time.Millisecond))
+		default:
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t\n", s.URL,
+				s.RTT.Round(time.Millisecond), s.Offset.Round(time.Millisecond))
+		}
+	}
+	_ = tw.Flush()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 오프셋이 양수면 로컬 시계가 서버들보다 느림
+	fmt.Printf("\nconsensus skew: %s (spread %s)\n",
+		r.Offset.Round(time.Millisecond), r.Spread.Round(time.Millisecond))
+}
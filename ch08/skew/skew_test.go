@@ -0,0 +1,156 @@
+package skew
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 테스트용 가짜 시계
+// Now 메서드를 호출할 때마다 step만큼 시간이 흐름
+type fakeClock struct {
+	mu   sync.Mutex
+	t    time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.t
+	c.t = c.t.Add(c.step)
+
+	return t
+}
+
+// 로컬 시계보다 offset만큼 빠른 시계를 가진 서버를 시작
+// 서버의 시각은 로컬 가짜 시계의 기준 시각으로부터 계산하므로, 실제 시계와 관계없이 결정적임
+func skewedServer(t *testing.T, base time.Time, offset time.Duration) string {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// 핸들러가 Date 헤더를 설정하면 서버는 덮어쓰지 않음
+			w.Header().Set("Date", base.Add(offset).UTC().Format(http.TimeFormat))
+		}))
+	t.Cleanup(ts.Close)
+
+	return ts.URL
+}
+
+// Date 헤더는 초 단위이므로, 기준 시각을 0.5초로 맞춰 오프셋이 정확히 계산되게 함
+var base = time.Date(2024, 1, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+
+func TestMeasureUsesMidpoint(t *testing.T) {
+	// 요청을 보낸 시각은 base, 응답을 받은 시각은 base + 2초이므로, 중간 시각은 base + 1초
+	// 서버는 그 시점에 로컬보다 5초 빠른 시각을 응답
+	url := skewedServer(t, base, 6*time.Second)
+	e := &Estimator{Now: (&fakeClock{t: base, step: 2 * time.Second}).Now}
+
+	s := e.Measure(context.Background(), url)
+	if s.Err != nil {
+		t.Fatal(s.Err)
+	}
+	if s.RTT != 2*time.Second {
+		t.Errorf("expected RTT 2s; actual %s", s.RTT)
+	}
+	if s.Offset != 5*time.Second {
+		t.Errorf("expected offset 5s; actual %s", s.Offset)
+	}
+}
+
+func TestEstimateRejectsOutliers(t *testing.T) {
+	urls := []string{
+		skewedServer(t, base, 10*time.Second),
+		skewedServer(t, base, 10*time.Second),
+		skewedServer(t, base, 11*time.Second),
+		skewedServer(t, base, 9*time.Second),
+		// 시계가 크게 어긋난 서버는 합의 값에 영향을 주지 않아야 함
+		skewedServer(t, base, time.Hour),
+	}
+
+	// Date 헤더가 없는 서버와 연결할 수 없는 서버
+	noDate := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header()["Date"] = nil
+		}))
+	defer noDate.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	urls = append(urls, noDate.URL, down.URL)
+
+	e := &Estimator{Now: (&fakeClock{t: base}).Now, Timeout: time.Second}
+	r, err := e.Estimate(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Offset != 10*time.Second {
+		t.Errorf("expected consensus offset 10s; actual %s", r.Offset)
+	}
+	if r.Spread != 2*time.Second {
+		t.Errorf("expected spread 2s; actual %s", r.Spread)
+	}
+	if !r.Samples[4].Outlier {
+		t.Errorf("expected sample %s to be an outlier", r.Samples[4].Offset)
+	}
+	for i := 0; i < 4; i++ {
+		if r.Samples[i].Outlier {
+			t.Errorf("sample %d (%s) rejected", i, r.Samples[i].Offset)
+		}
+	}
+	if r.Samples[5].Err == nil || r.Samples[6].Err == nil {
+		t.Errorf("expected errors for failing servers")
+	}
+}
+
+func TestEstimateNoSamples(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	e := &Estimator{}
+	_, err := e.Estimate(context.Background(), []string{down.URL})
+	if !errors.Is(err, ErrNoSamples) {
+		t.Fatalf("expected ErrNoSamples; actual %v", err)
+	}
+}
+
+func TestEstimateNoConsensus(t *testing.T) {
+	// 중앙값은 두 오프셋의 평균인 5초이므로, 두 샘플 모두 임곗값 1초를 벗어남
+	urls := []string{
+		skewedServer(t, base, 0),
+		skewedServer(t, base, 10*time.Second),
+	}
+
+	e := &Estimator{Now: (&fakeClock{t: base}).Now, Threshold: time.Second}
+	r, err := e.Estimate(context.Background(), urls)
+	if !errors.Is(err, ErrNoConsensus) {
+		t.Fatalf("expected ErrNoConsensus; actual %v", err)
+	}
+	for i, s := range r.Samples {
+		if !s.Outlier {
+			t.Errorf("expected sample %d (%s) to be an outlier", i, s.Offset)
+		}
+	}
+}
+
+func TestMedian(t *testing.T) {
+	for _, c := range []struct {
+		in       []time.Duration
+		expected time.Duration
+	}{
+		{[]time.Duration{3, 1, 2}, 2},
+		{[]time.Duration{4, 1, 3, 2}, 2},
+		{[]time.Duration{-1}, -1},
+	} {
+		if actual := median(c.in); actual != c.expected {
+			t.Errorf("median(%v): expected %d; actual %d", c.in, c.expected,
+				actual)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/awoodbeck/gnp/ch08/trace"
+)
+
+var (
+	method   = flag.String("X", http.MethodGet, "request method")
+	format   = flag.String("w", "", "curl-style output format, e.g. '%{time_total}\\n'")
+	output   = flag.String("o", "", "write the response body to this file")
+	count    = flag.Int("n", 1, "number of requests over the same client")
+	insecure = flag.Bool("k", false, "skip TLS certificate verification")
+	timeout  = flag.Duration("timeout", 30*time.Second, "per-request timeout")
+)
+
+func init() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(),
+			"Usage:\n\t%s [flags] URL\n\nFormat variables: %s\n",
+			filepath.Base(os.Args[0]), strings.Join(variableNames(), " "))
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	body := io.Discard
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+		body = f
+	}
+
+	var timings *trace.Timings
+	client := &http.Client{
+		Transport: &trace.Transport{
+			Transport: &http.Transport{
+				Proxy:             http.ProxyFromEnvironment,
+				ForceAttemptHTTP2: true,
+				TLSClientConfig:   &tls.Config{InsecureSkipVerify: *insecure},
+			},
+			OnDone: func(t *trace.Timings) { timings = t },
+		},
+	}
+
+	for i := 0; i < *count; i++ {
+		err := fetch(client, flag.Arg(0), body)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if *format != "" {
+			fmt.Print(expand(unescape(*format), timings))
+		} else {
+			printTimings(timings)
+		}
+	}
+}
+
+func fetch(client *http.Client, url string, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, *method, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+}
+
+// 단계별 소요 시간과 누적 시간을 표로 출력
+func printTimings(t *trace.Timings) {
+	fmt.Printf("%s %s -> %d (%s, reused=%t)\n", t.Method, t.URL, t.StatusCode,
+		t.RemoteAddr, t.Reused)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "PHASE\tDURATION\tCUMULATIVE\t")
+	for _, p := range []struct {
+		name          string
+		d, cumulative time.Duration
+	}{
+		{"DNS lookup", t.DNS(), t.NameLookup},
+		{"TCP connect", t.TCP(), t.Connect},
+		{"TLS handshake", t.TLS(), t.AppConnect},
+		{"Server processing", t.Server(), t.StartTransfer},
+		{"Content transfer", t.Transfer(), t.Total},
+	} {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t\n", p.name, ms(p.d),
+			ms(p.cumulative))
+	}
+	_ = tw.Flush()
+	fmt.Println()
+}
+
+func ms(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3,
+		64) + "ms"
+}
+
+// curl -w 변수와 같이 초 단위로 출력
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 6, 64)
+}
+
+var variables = map[string]func(*trace.Timings) string{
+	"time_namelookup":    func(t *trace.Timings) string { return seconds(t.NameLookup) },
+	"time_connect":       func(t *trace.Timings) string { return seconds(t.Connect) },
+	"time_appconnect":    func(t *trace.Timings) string { return seconds(t.AppConnect) },
+	"time_pretransfer":   func(t *trace.Timings) string { return seconds(t.PreTransfer) },
+	"time_starttransfer": func(t *trace.Timings) string { return seconds(t.StartTransfer) },
+	"time_total":         func(t *trace.Timings) string { return seconds(t.Total) },
+	"http_code":          func(t *trace.Timings) string { return fmt.Sprintf("%03d", t.StatusCode) },
+	"size_download":      func(t *trace.Timings) string { return strconv.FormatInt(t.BodySize, 10) },
+	"url_effective":      func(t *trace.Timings) string { return t.URL },
+	"num_connects": func(t *trace.Timings) string {
+		if t.Reused {
+			return "0"
+		}
+		return "1"
+	},
+	"remote_ip": func(t *trace.Timings) string {
+		host, _, _ := net.SplitHostPort(t.RemoteAddr)
+		return host
+	},
+	"remote_port": func(t *trace.Timings) string {
+		_, port, _ := net.SplitHostPort(t.RemoteAddr)
+		return port
+	},
+	"local_port": func(t *trace.Timings) string {
+		_, port, _ := net.SplitHostPort(t.LocalAddr)
+		return port
+	},
+}
+
+func variableNames() []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// %{name}을 해당 값으로 치환. 알 수 없는 변수는 그대로 둠
+func expand(format string, t *trace.Timings) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(format, "%{")
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(format[i:], '}')
+		if j < 0 {
+			break
+		}
+
+		b.WriteString(format[:i])
+		name := format[i+2 : i+j]
+		if f, ok := variables[name]; ok {
+			b.WriteString(f(t))
+		} else {
+			b.WriteString(format[i : i+j+1])
+		}
+		format = format[i+j+1:]
+	}
+	b.WriteString(format)
+
+	return b.String()
+}
+
+// 셸에서 작은따옴표로 넘긴 \n과 \t를 실제 문자로 바꿈
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r").Replace(s)
+}
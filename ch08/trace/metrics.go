@@ -0,0 +1,81 @@
+package trace
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 요청 단계별 소요 시간과 연결 재사용 횟수를 기록하는 프로메테우스 수집기
+// prometheus.Register 함수로 등록해 사용
+type Metrics struct {
+	phases   *prometheus.HistogramVec
+	conns    *prometheus.CounterVec
+	requests *prometheus.CounterVec
+}
+
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		phases: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http_client",
+			Name:      "phase_duration_seconds",
+			Help:      "HTTP client request phase durations.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, []string{"phase"}),
+		conns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http_client",
+			Name:      "connections_total",
+			Help:      "HTTP client connections by reuse.",
+		}, []string{"reused"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http_client",
+			Name:      "requests_total",
+			Help:      "HTTP client requests by status code.",
+		}, []string{"code"}),
+	}
+}
+
+// prometheus.Collector 인터페이스 구현
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.phases.Describe(ch)
+	m.conns.Describe(ch)
+	m.requests.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.phases.Collect(ch)
+	m.conns.Collect(ch)
+	m.requests.Collect(ch)
+}
+
+func (m *Metrics) observe(t *Timings) {
+	code := "error"
+	if t.Err == nil {
+		code = strconv.Itoa(t.StatusCode)
+	}
+	m.requests.WithLabelValues(code).Inc()
+
+	// 연결을 얻지 못한 요청은 단계별 시간을 기록하지 않음
+	if t.PreTransfer == 0 {
+		return
+	}
+
+	if t.Reused {
+		m.conns.WithLabelValues("true").Inc()
+	} else {
+		m.conns.WithLabelValues("false").Inc()
+		m.phases.WithLabelValues("dns").Observe(t.DNS().Seconds())
+		m.phases.WithLabelValues("tcp").Observe(t.TCP().Seconds())
+		if t.AppConnect > 0 {
+			m.phases.WithLabelValues("tls").Observe(t.TLS().Seconds())
+		}
+	}
+	if t.StartTransfer > 0 {
+		m.phases.WithLabelValues("server").Observe(t.Server().Seconds())
+		m.phases.WithLabelValues("transfer").Observe(t.Transfer().Seconds())
+	}
+	m.phases.WithLabelValues("total").Observe(t.Total.Seconds())
+}
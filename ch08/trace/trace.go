@@ -0,0 +1,240 @@
+package trace
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// 요청 하나의 단계별 소요 시간
+// curl -w의 time_* 변수와 같이, 각 값은 요청을 시작한 시점부터 해당 단계가 끝날 때까지의 누적 시간
+// 재사용된 연결이라면 DNS 조회, TCP 연결, TLS 핸드셰이크 단계는 0
+type Timings struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Err        error // 요청이나 body 읽기에 실패했다면 그 에러
+
+	Start         time.Time
+	NameLookup    time.Duration // DNS 조회 완료
+	Connect       time.Duration // TCP 연결 완료
+	AppConnect    time.Duration // TLS 핸드셰이크 완료. TLS를 사용하지 않으면 0
+	PreTransfer   time.Duration // 연결을 얻어 요청을 보내기 직전
+	StartTransfer time.Duration // 응답의 첫 번째 바이트 수신 (TTFB)
+	Total         time.Duration // 응답 body를 끝까지 읽거나 닫음
+
+	Reused     bool          // 커넥션 풀의 연결을 재사용했는지 여부
+	WasIdle    bool          // 재사용한 연결이 유휴 상태였는지 여부
+	IdleTime   time.Duration // 재사용한 연결이 유휴 상태였던 시간
+	LocalAddr  string
+	RemoteAddr string
+	TLSVersion uint16 // TLS를 사용하지 않으면 0
+	BodySize   int64  // 읽은 응답 body의 크기
+}
+
+// 단계별 소요 시간 (누적 시간이 아닌 각 단계의 시간)
+func (t *Timings) DNS() time.Duration { return t.NameLookup }
+
+func (t *Timings) TCP() time.Duration {
+	if t.Connect == 0 {
+		return 0
+	}
+	return t.Connect - t.NameLookup
+}
+
+func (t *Timings) TLS() time.Duration {
+	if t.AppConnect == 0 {
+		return 0
+	}
+	return t.AppConnect - t.Connect
+}
+
+// 요청을 보낸 후 첫 번째 바이트를 받을 때까지의 서버 처리 시간
+func (t *Timings) Server() time.Duration { return t.StartTransfer - t.PreTransfer }
+
+// 첫 번째 바이트 이후 body를 모두 받을 때까지의 시간
+func (t *Timings) Transfer() time.Duration { return t.Total - t.StartTransfer }
+
+func (t *Timings) String() string {
+	s := fmt.Sprintf("%s %s", t.Method, t.URL)
+	if t.Err != nil {
+		s += fmt.Sprintf(" error=%q", t.Err.Error())
+	} else {
+		s += fmt.Sprintf(" status=%d", t.StatusCode)
+	}
+
+	return s + fmt.Sprintf(" reused=%t dns=%s tcp=%s tls=%s server=%s "+
+		"transfer=%s total=%s size=%d", t.Reused, t.DNS(), t.TCP(), t.TLS(),
+		t.Server(), t.Transfer(), t.Total, t.BodySize)
+}
+
+// Transport는 net/http/httptrace로 요청마다 단계별 소요 시간과 연결 재사용 여부를 측정하는 http.RoundTripper
+// 측정은 응답 body를 끝까지 읽거나 닫을 때 끝나며, 그때 OnDone 함수를 호출하고 Metrics에 기록함
+type Transport struct {
+	// 실제 요청을 보낼 RoundTripper. nil이면 http.DefaultTransport를 사용
+	Transport http.RoundTripper
+
+	// nil이 아니면, 요청마다 측정이 끝났을 때 호출됨. 요청별 로그를 남길 때 사용
+	OnDone func(*Timings)
+
+	// nil이 아니면, 측정 결과를 기록
+	Metrics *Metrics
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+
+	return http.DefaultTransport
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := &recorder{t: Timings{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Start:  time.Now(),
+	}}
+
+	ctx := httptrace.WithClientTrace(req.Context(), r.clientTrace())
+	resp, err := t.transport().RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		r.t.Err = err
+		t.done(r)
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.t.StatusCode = resp.StatusCode
+	if resp.TLS != nil {
+		r.t.TLSVersion = resp.TLS.Version
+	}
+	r.mu.Unlock()
+
+	resp.Body = &body{ReadCloser: resp.Body, r: r, done: func() { t.done(r) }}
+
+	return resp, nil
+}
+
+func (t *Transport) done(r *recorder) {
+	r.mu.Lock()
+	if r.t.Total == 0 {
+		r.t.Total = time.Since(r.t.Start)
+	}
+	timings := r.t
+	r.mu.Unlock()
+
+	if t.Metrics != nil {
+		t.Metrics.observe(&timings)
+	}
+	if t.OnDone != nil {
+		t.OnDone(&timings)
+	}
+}
+
+// httptrace 훅에서 시각을 기록
+// 여러 주소로 동시에 연결을 시도하면(happy eyeballs) 훅이 여러 고루틴에서 호출될 수 있으므로 잠금이 필요함
+type recorder struct {
+	mu sync.Mutex
+	t  Timings
+}
+
+func (r *recorder) since() time.Duration { return time.Since(r.t.Start) }
+
+func (r *recorder) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			r.mu.Lock()
+			r.t.NameLookup = r.since()
+			r.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			r.mu.Lock()
+			// 가장 먼저 성공한 연결을 기록
+			if err == nil && r.t.Connect == 0 {
+				r.t.Connect = r.since()
+			}
+			r.mu.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			r.mu.Lock()
+			if err == nil {
+				r.t.AppConnect = r.since()
+			}
+			r.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.mu.Lock()
+			r.t.PreTransfer = r.since()
+			r.t.Reused = info.Reused
+			r.t.WasIdle = info.WasIdle
+			r.t.IdleTime = info.IdleTime
+			r.t.LocalAddr = info.Conn.LocalAddr().String()
+			r.t.RemoteAddr = info.Conn.RemoteAddr().String()
+			r.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			r.mu.Lock()
+			r.t.StartTransfer = r.since()
+			r.mu.Unlock()
+		},
+	}
+}
+
+// body를 끝까지 읽거나 닫으면 측정을 마치는 응답 body
+type body struct {
+	io.ReadCloser
+	r    *recorder
+	once sync.Once
+	done func()
+}
+
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.r.mu.Lock()
+	b.r.t.BodySize += int64(n)
+	b.r.mu.Unlock()
+
+	if err != nil {
+		b.finish(err)
+	}
+
+	return n, err
+}
+
+func (b *body) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish(nil)
+
+	return err
+}
+
+func (b *body) finish(err error) {
+	b.once.Do(func() {
+		b.r.mu.Lock()
+		b.r.t.Total = b.r.since()
+		if err != nil && err != io.EOF {
+			b.r.t.Err = err
+		}
+		b.r.mu.Unlock()
+
+		b.done()
+	})
+}
+
+// 측정 결과를 한 줄씩 w에 기록하는 OnDone 함수를 반환
+func Logger(w io.Writer) func(*Timings) {
+	var mu sync.Mutex
+
+	return func(t *Timings) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, _ = fmt.Fprintln(w, t)
+	}
+}
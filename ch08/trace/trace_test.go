@@ -0,0 +1,191 @@
+package trace
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTransportReuse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("Hello"))
+		},
+	))
+	defer srv.Close()
+
+	var (
+		mu      sync.Mutex
+		timings []*Timings
+	)
+	tr := &Transport{
+		Transport: &http.Transport{},
+		OnDone: func(t *Timings) {
+			mu.Lock()
+			timings = append(timings, t)
+			mu.Unlock()
+		},
+		Metrics: NewMetrics("test"),
+	}
+	client := &http.Client{Transport: tr}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "Hello" {
+			t.Fatalf("expected %q; actual %q", "Hello", b)
+		}
+	}
+
+	if len(timings) != 2 {
+		t.Fatalf("expected 2 timings; actual %d", len(timings))
+	}
+
+	first, second := timings[0], timings[1]
+	if first.Reused {
+		t.Error("expected first request to use a new connection")
+	}
+	if first.Connect == 0 {
+		t.Error("expected first request to record connect time")
+	}
+	if !second.Reused || !second.WasIdle {
+		t.Error("expected second request to reuse an idle connection")
+	}
+	if second.Connect != 0 {
+		t.Errorf("expected no connect time on reuse; actual %s", second.Connect)
+	}
+
+	for i, tm := range timings {
+		if tm.StatusCode != http.StatusOK {
+			t.Errorf("%d: expected status %d; actual %d", i, http.StatusOK,
+				tm.StatusCode)
+		}
+		if tm.BodySize != 5 {
+			t.Errorf("%d: expected body size 5; actual %d", i, tm.BodySize)
+		}
+		if tm.RemoteAddr != srv.Listener.Addr().String() {
+			t.Errorf("%d: expected remote address %q; actual %q", i,
+				srv.Listener.Addr(), tm.RemoteAddr)
+		}
+		if !(tm.PreTransfer <= tm.StartTransfer &&
+			tm.StartTransfer <= tm.Total) {
+			t.Errorf("%d: phases out of order: %+v", i, tm)
+		}
+	}
+
+	m := tr.Metrics
+	if v := testutil.ToFloat64(m.conns.WithLabelValues("true")); v != 1 {
+		t.Errorf("expected 1 reused connection; actual %v", v)
+	}
+	if v := testutil.ToFloat64(m.conns.WithLabelValues("false")); v != 1 {
+		t.Errorf("expected 1 new connection; actual %v", v)
+	}
+	if v := testutil.ToFloat64(m.requests.WithLabelValues("200")); v != 2 {
+		t.Errorf("expected 2 requests; actual %v", v)
+	}
+}
+
+func TestTransportTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+	defer srv.Close()
+
+	var timings *Timings
+	client := srv.Client()
+	client.Transport = &Transport{
+		Transport: client.Transport,
+		OnDone:    func(t *Timings) { timings = t },
+	}
+
+	resp, err := client.Head(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if timings == nil {
+		t.Fatal("expected timings")
+	}
+	if timings.AppConnect == 0 || timings.AppConnect < timings.Connect {
+		t.Errorf("expected TLS handshake after connect: %+v", timings)
+	}
+	if timings.TLSVersion == 0 {
+		t.Error("expected TLS version")
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	var buf bytes.Buffer
+	metrics := NewMetrics("test")
+	client := &http.Client{
+		Transport: &Transport{OnDone: Logger(&buf), Metrics: metrics},
+	}
+
+	_, err := client.Get(url)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	line := buf.String()
+	if !strings.HasPrefix(line, "GET "+url+" error=") {
+		t.Fatalf("unexpected log line %q", line)
+	}
+	if v := testutil.ToFloat64(metrics.requests.WithLabelValues("error")); v != 1 {
+		t.Errorf("expected 1 failed request; actual %v", v)
+	}
+	if n := testutil.CollectAndCount(metrics, "test_http_client_connections_total"); n != 0 {
+		t.Errorf("expected no connections; actual %d", n)
+	}
+}
+
+func TestTransportBodyError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// 선언한 길이보다 적게 쓰고 연결을 닫아 body를 읽는 중 에러를 유발
+			w.Header().Set("Content-Length", "10")
+			_, _ = w.Write([]byte("short"))
+		},
+	))
+	defer srv.Close()
+
+	var timings *Timings
+	client := &http.Client{
+		Transport: &Transport{OnDone: func(t *Timings) { timings = t }},
+	}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err == nil {
+		t.Fatal("expected a body read error")
+	}
+
+	if timings == nil || !errors.Is(timings.Err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected %v; actual %+v", io.ErrUnexpectedEOF, timings)
+	}
+	if timings.BodySize != 5 {
+		t.Errorf("expected body size 5; actual %d", timings.BodySize)
+	}
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 사용자 이름별 bcrypt 패스워드 해시
+type Credentials map[string][]byte
+
+// 존재하지 않는 사용자를 확인할 때도 같은 시간이 걸리도록 비교에 사용할 해시
+// 해시를 만드는 데 시간이 걸리므로 처음 필요할 때 비용별로 한 번만 만듦
+type dummyHash struct {
+	once sync.Once
+	hash []byte
+}
+
+var (
+	dummyMu     sync.Mutex
+	dummyHashes = make(map[int]*dummyHash)
+)
+
+// 주어진 비용의 더미 해시를 반환
+func dummyHashFor(cost int) []byte {
+	dummyMu.Lock()
+	d, ok := dummyHashes[cost]
+	if !ok {
+		d = new(dummyHash)
+		dummyHashes[cost] = d
+	}
+	dummyMu.Unlock()
+
+	d.once.Do(func() {
+		d.hash, _ = bcrypt.GenerateFromPassword([]byte("dummy"), cost)
+	})
+
+	return d.hash
+}
+
+// 저장된 해시 중 가장 큰 비용을 반환. 해시가 없으면 bcrypt.DefaultCost
+// 더미 해시의 비교 시간이 실제 사용자의 비교 시간과 같아지도록 사용
+func (c Credentials) cost() int {
+	cost := bcrypt.DefaultCost
+	if len(c) > 0 {
+		cost = bcrypt.MinCost
+	}
+	for _, hash := range c {
+		if hc, err := bcrypt.Cost(hash); err == nil && hc > cost {
+			cost = hc
+		}
+	}
+
+	return cost
+}
+
+// htpasswd -B 명령으로 만든 형식의 자격 증명 파일을 읽음
+// 각 줄은 "사용자:bcrypt 해시" 형식이며, 빈 줄과 #으로 시작하는 줄은 무시
+func LoadCredentials(path string) (Credentials, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	c, err := ParseCredentials(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+func ParseCredentials(r io.Reader) (Credentials, error) {
+	c := make(Credentials)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok || user == "" {
+			return nil, fmt.Errorf("line %d: expected user:hash", n)
+		}
+		// 평문이나 MD5, SHA1 해시는 안전하지 않으므로 bcrypt 해시만 허용
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("line %d: user %q: %w", n, user, err)
+		}
+		c[user] = []byte(hash)
+	}
+
+	return c, scanner.Err()
+}
+
+// 사용자 이름과 패스워드가 일치하는지 확인
+// 사용자가 없어도 해시를 비교해, 응답 시간으로 사용자의 존재 여부를 알아낼 수 없도록 함
+func (c Credentials) Verify(user, password string) bool {
+	hash, ok := c[user]
+	if !ok {
+		hash = dummyHashFor(c.cost())
+	}
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+
+	return ok && err == nil
+}
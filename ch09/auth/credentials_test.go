@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// htpasswd -B 명령은 $2y$ 접두사를 사용
+	apache := "$2y$" + string(hash[4:])
+
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	err = os.WriteFile(path, []byte(fmt.Sprintf(
+		"# users\nalice:%s\n\nbob:%s\n", hash, apache)), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadCredentials(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		user, password string
+		ok             bool
+	}{
+		{"alice", "s3cret", true},
+		{"bob", "s3cret", true},
+		{"alice", "wrong", false},
+		{"carol", "s3cret", false},
+		{"", "", false},
+	}
+
+	for i, c2 := range testCases {
+		if actual := c.Verify(c2.user, c2.password); actual != c2.ok {
+			t.Errorf("%d: expected %t; actual %t", i, c2.ok, actual)
+		}
+	}
+}
+
+func TestParseCredentialsErrors(t *testing.T) {
+	for i, line := range []string{
+		"alice",
+		":$2a$04$abc",
+		"alice:plaintext",
+		"alice:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+	} {
+		_, err := ParseCredentials(strings.NewReader(line))
+		if err == nil {
+			t.Errorf("%d: expected an error for %q", i, line)
+		}
+	}
+}
+
+func TestDummyHashCost(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 존재하지 않는 사용자의 비교에는 저장된 해시와 같은 비용의 더미 해시를 사용
+	c := Credentials{"alice": hash}
+	cost, err := bcrypt.Cost(dummyHashFor(c.cost()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.MinCost {
+		t.Fatalf("expected cost %d; actual %d", bcrypt.MinCost, cost)
+	}
+	if actual := Credentials(nil).cost(); actual != bcrypt.DefaultCost {
+		t.Fatalf("expected cost %d; actual %d", bcrypt.DefaultCost, actual)
+	}
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	_ "crypto/sha256" // HS256
+	_ "crypto/sha512" // HS384, HS512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrAlgorithm      = errors.New("unsupported token algorithm")
+	ErrSignature      = errors.New("invalid token signature")
+	ErrExpired        = errors.New("token expired")
+	ErrNotYetValid    = errors.New("token not yet valid")
+	ErrIssuer         = errors.New("unexpected token issuer")
+	ErrAudience       = errors.New("unexpected token audience")
+)
+
+// 시계 오차를 감안해 exp, nbf 클레임에 허용하는 여유 시간
+const clockSkew = time.Minute
+
+// 지원하는 HMAC 서명 알고리즘
+// "none"이나 공개 키 알고리즘은 허용하지 않으므로 알고리즘 혼동 공격을 막을 수 있음
+var algorithms = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+}
+
+// JWT의 등록된 클레임
+type Claims struct {
+	Subject   string   `json:"sub,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+}
+
+// aud 클레임은 문자열 하나 또는 문자열 배열
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+
+	return nil
+}
+
+// JWT를 검증하는 데 필요한 설정
+type TokenValidator struct {
+	Key      []byte // HMAC 비밀 키
+	Issuer   string // 비어 있지 않으면 iss 클레임이 일치해야 함
+	Audience string // 비어 있지 않으면 aud 클레임에 포함돼야 함
+
+	// 현재 시각을 반환하는 함수. nil이면 time.Now를 사용
+	Now func() time.Time
+}
+
+// HMAC으로 서명된 JWT의 서명과 클레임을 검증하고, 검증된 클레임을 반환
+// 인증 서버에 묻지 않고 로컬에서 검증하므로, 키를 공유하는 서비스끼리만 사용해야 함
+func (v *TokenValidator) Validate(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	hash, ok := algorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrAlgorithm, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !hmac.Equal(sig, sign(hash, v.Key, parts[0]+"."+parts[1])) {
+		return nil, ErrSignature
+	}
+
+	// 서명을 확인한 후에만 클레임을 해석
+	claims := new(Claims)
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if claims.ExpiresAt != 0 &&
+		!now.Before(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, ErrExpired
+	}
+	if claims.NotBefore != 0 &&
+		now.Before(time.Unix(claims.NotBefore, 0).Add(-clockSkew)) {
+		return nil, ErrNotYetValid
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return nil, ErrIssuer
+	}
+	if v.Audience != "" && !contains(claims.Audience, v.Audience) {
+		return nil, ErrAudience
+	}
+
+	return claims, nil
+}
+
+// 클레임에 HS256으로 서명한 JWT를 반환
+func Sign(key []byte, claims *Claims) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	s := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	return s + "." + base64.RawURLEncoding.EncodeToString(
+		sign(crypto.SHA256, key, s)), nil
+}
+
+func sign(hash crypto.Hash, key []byte, s string) []byte {
+	mac := hmac.New(hash.New, key)
+	_, _ = mac.Write([]byte(s))
+
+	return mac.Sum(nil)
+}
+
+func decodeSegment(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedToken, err)
+	}
+
+	return nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
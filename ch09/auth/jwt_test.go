@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenValidator(t *testing.T) {
+	key := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	v := &TokenValidator{
+		Key:      key,
+		Issuer:   "gnp",
+		Audience: "ch09",
+		Now:      func() time.Time { return now },
+	}
+
+	valid := Claims{
+		Subject:   "alice",
+		Issuer:    "gnp",
+		Audience:  Audience{"ch09", "other"},
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+
+	testCases := []struct {
+		claims Claims
+		key    []byte
+		err    error
+	}{
+		{valid, key, nil},
+		{valid, []byte("wrong"), ErrSignature},
+		{Claims{Subject: "alice", Issuer: "gnp", Audience: Audience{"ch09"},
+			ExpiresAt: now.Add(-2 * time.Minute).Unix()}, key, ErrExpired},
+		// 시계 오차 범위 안의 만료는 허용
+		{Claims{Subject: "alice", Issuer: "gnp", Audience: Audience{"ch09"},
+			ExpiresAt: now.Add(-30 * time.Second).Unix()}, key, nil},
+		{Claims{Subject: "alice", Issuer: "gnp", Audience: Audience{"ch09"},
+			NotBefore: now.Add(time.Hour).Unix()}, key, ErrNotYetValid},
+		{Claims{Subject: "alice", Issuer: "evil", Audience: Audience{"ch09"}},
+			key, ErrIssuer},
+		{Claims{Subject: "alice", Issuer: "gnp", Audience: Audience{"other"}},
+			key, ErrAudience},
+	}
+
+	for i, c := range testCases {
+		c := c
+		token, err := Sign(c.key, &c.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := v.Validate(token)
+		if !errors.Is(err, c.err) {
+			t.Errorf("%d: expected %v; actual %v", i, c.err, err)
+			continue
+		}
+		if err == nil && claims.Subject != "alice" {
+			t.Errorf("%d: expected %q; actual %q", i, "alice", claims.Subject)
+		}
+	}
+}
+
+func TestTokenValidatorMalformed(t *testing.T) {
+	v := &TokenValidator{Key: []byte("secret")}
+	token, err := Sign(v.Key, &Claims{Subject: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	enc := base64.RawURLEncoding.EncodeToString
+
+	testCases := []struct {
+		token string
+		err   error
+	}{
+		{"", ErrMalformedToken},
+		{"a.b", ErrMalformedToken},
+		{parts[0] + "." + parts[1] + ".!!!", ErrMalformedToken},
+		// 서명하지 않은 토큰은 알고리즘 단계에서 거부
+		{enc([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".", ErrAlgorithm},
+		{enc([]byte(`{"alg":"RS256"}`)) + "." + parts[1] + "." + parts[2],
+			ErrAlgorithm},
+		// 같은 서명으로 클레임을 바꿔치기
+		{parts[0] + "." + enc([]byte(`{"sub":"admin"}`)) + "." + parts[2],
+			ErrSignature},
+	}
+
+	for i, c := range testCases {
+		_, err := v.Validate(c.token)
+		if !errors.Is(err, c.err) {
+			t.Errorf("%d: expected %v; actual %v", i, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 요청을 인증하고, 인증된 주체를 요청의 context에 담아 다음 핸들러로 넘기는 미들웨어의 설정
+// 설정된 방식만 사용하며, 다음 순서로 확인함
+//
+//  1. 검증된 클라이언트 인증서 (ClientCerts)
+//  2. Authorization: Basic 헤더 (Credentials)
+//  3. Authorization: Bearer 헤더 (Tokens)
+//
+// 어느 방식으로도 인증하지 못하면 WWW-Authenticate 헤더와 함께 401 Unauthorized를 응답
+type Authenticator struct {
+	// nil이 아니면 HTTP 기본 인증을 허용
+	Credentials Credentials
+
+	// nil이 아니면 HMAC으로 서명된 JWT 베어러 토큰을 허용
+	Tokens *TokenValidator
+
+	// true이면 TLS 핸드셰이크에서 검증된 클라이언트 인증서를 허용
+	// 서버의 tls.Config에 ClientCAs와 ClientAuth를 설정해야 함
+	ClientCerts bool
+
+	// WWW-Authenticate 헤더의 realm. 비어 있으면 "restricted"
+	Realm string
+}
+
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			p, err := a.Authenticate(r)
+			if err != nil {
+				a.challenge(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+		},
+	)
+}
+
+// 요청을 인증해 인증된 주체를 반환
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, error) {
+	if a.ClientCerts {
+		if p, ok := certPrincipal(r); ok {
+			return p, nil
+		}
+	}
+
+	scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	switch {
+	case strings.EqualFold(scheme, "Basic") && a.Credentials != nil:
+		user, password, ok := r.BasicAuth()
+		if !ok || !a.Credentials.Verify(user, password) {
+			return Principal{}, errInvalidCredentials
+		}
+		return Principal{Name: user, Method: MethodBasic}, nil
+	case strings.EqualFold(scheme, "Bearer") && a.Tokens != nil:
+		claims, err := a.Tokens.Validate(strings.TrimSpace(credentials))
+		if err != nil {
+			return Principal{}, &tokenError{err}
+		}
+		if claims.Subject == "" {
+			return Principal{}, &tokenError{fmt.Errorf("%w: missing sub",
+				ErrMalformedToken)}
+		}
+		return Principal{Name: claims.Subject, Method: MethodBearer,
+			Claims: claims}, nil
+	}
+
+	return Principal{}, errNoCredentials
+}
+
+var (
+	errNoCredentials      = errors.New("no credentials")
+	errInvalidCredentials = errors.New("invalid credentials")
+)
+
+// RFC 6750에 따라 베어러 토큰의 검증 실패 이유를 WWW-Authenticate 헤더로 알려주기 위한 에러
+type tokenError struct{ err error }
+
+func (e *tokenError) Error() string { return e.err.Error() }
+func (e *tokenError) Unwrap() error { return e.err }
+
+func (a *Authenticator) challenge(w http.ResponseWriter, err error) {
+	realm := a.Realm
+	if realm == "" {
+		realm = "restricted"
+	}
+
+	h := w.Header()
+	if a.Credentials != nil {
+		h.Add("WWW-Authenticate", fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"",
+			realm))
+	}
+	if a.Tokens != nil {
+		if te, ok := err.(*tokenError); ok {
+			h.Add("WWW-Authenticate", fmt.Sprintf(
+				"Bearer realm=%q, error=\"invalid_token\", error_description=%q",
+				realm, te.Error()))
+		} else {
+			h.Add("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q", realm))
+		}
+	}
+
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
+
+// TLS 핸드셰이크에서 검증된 클라이언트 인증서의 주체를 반환
+// 검증되지 않은 인증서는 누구나 만들 수 있으므로 VerifiedChains만 사용
+func certPrincipal(r *http.Request) (Principal, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 ||
+		len(r.TLS.VerifiedChains[0]) == 0 {
+		return Principal{}, false
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	name := certName(cert)
+	if name == "" {
+		return Principal{}, false
+	}
+
+	return Principal{Name: name, Method: MethodMTLS, NotAfter: cert.NotAfter},
+		true
+}
+
+// 인증서의 CN을 사용하고, 없으면 첫 번째 이메일 주소나 DNS 이름을 사용
+func certName(cert *x509.Certificate) string {
+	switch {
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	}
+
+	return ""
+}
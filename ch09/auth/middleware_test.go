@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthenticator(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := []byte("secret")
+	a := &Authenticator{
+		Credentials: Credentials{"alice": hash},
+		Tokens:      &TokenValidator{Key: key},
+		ClientCerts: true,
+	}
+
+	handler := a.Middleware(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			p, ok := FromContext(r.Context())
+			if !ok {
+				t.Error("expected a principal")
+			}
+			_, _ = w.Write([]byte(p.Method + ":" + p.Name))
+		},
+	))
+
+	token, err := Sign(key, &Claims{Subject: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noSubject, err := Sign(key, &Claims{Issuer: "gnp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "carol"}}
+	verified := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+	unverified := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+
+	testCases := []struct {
+		authorization string
+		tls           *tls.ConnectionState
+		code          int
+		body          string
+	}{
+		{"", nil, http.StatusUnauthorized, ""},
+		{basic("alice", "s3cret"), nil, http.StatusOK, "basic:alice"},
+		{basic("alice", "wrong"), nil, http.StatusUnauthorized, ""},
+		{"Bearer " + token, nil, http.StatusOK, "bearer:bob"},
+		{"bearer " + token, nil, http.StatusOK, "bearer:bob"},
+		{"Bearer " + token + "x", nil, http.StatusUnauthorized, ""},
+		{"Bearer " + noSubject, nil, http.StatusUnauthorized, ""},
+		{"", verified, http.StatusOK, "mtls:carol"},
+		// 검증된 인증서가 헤더보다 우선
+		{basic("alice", "s3cret"), verified, http.StatusOK, "mtls:carol"},
+		{"", unverified, http.StatusUnauthorized, ""},
+		{"Digest foo", nil, http.StatusUnauthorized, ""},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+		if c.authorization != "" {
+			r.Header.Set("Authorization", c.authorization)
+		}
+		r.TLS = c.tls
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, resp.StatusCode)
+			continue
+		}
+		if c.code == http.StatusOK {
+			if actual := w.Body.String(); actual != c.body {
+				t.Errorf("%d: expected %q; actual %q", i, c.body, actual)
+			}
+			continue
+		}
+
+		challenges := resp.Header.Values("WWW-Authenticate")
+		if len(challenges) != 2 ||
+			!strings.HasPrefix(challenges[0], `Basic realm="restricted"`) ||
+			!strings.HasPrefix(challenges[1], `Bearer realm="restricted"`) {
+			t.Errorf("%d: unexpected challenges %q", i, challenges)
+		}
+		if strings.HasPrefix(c.authorization, "Bearer") &&
+			!strings.Contains(challenges[1], `error="invalid_token"`) {
+			t.Errorf("%d: expected invalid_token error; actual %q", i,
+				challenges[1])
+		}
+	}
+}
+
+func basic(user, password string) string {
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	r.SetBasicAuth(user, password)
+
+	return r.Header.Get("Authorization")
+}
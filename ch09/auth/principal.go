@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// 인증 방식
+const (
+	MethodBasic  = "basic"
+	MethodBearer = "bearer"
+	MethodMTLS   = "mtls"
+)
+
+// 인증된 주체
+type Principal struct {
+	Name   string // 사용자 이름, 토큰의 sub 클레임 또는 인증서의 CN
+	Method string // 인증 방식 (MethodBasic, MethodBearer, MethodMTLS)
+
+	// 베어러 토큰으로 인증한 경우, 검증된 토큰의 클레임
+	Claims *Claims
+
+	// 클라이언트 인증서로 인증한 경우, 인증서의 만료 시각
+	NotAfter time.Time
+}
+
+type principalKey struct{}
+
+// 인증된 주체를 담은 context를 반환
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// context에 담긴 인증된 주체를 반환
+// 인증 미들웨어를 거치지 않은 요청이라면 false를 반환
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+
+	return p, ok
+}
@@ -0,0 +1,9 @@
+package main
+
+import "embed"
+
+// -embed 플래그를 지정하면 디스크 대신 바이너리에 임베드한 정적 파일을 제공
+// "."으로 시작하는 파일과 디렉터리는 임베드되지 않음
+//
+//go:embed files
+var embedded embed.FS
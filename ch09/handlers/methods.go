@@ -25,7 +25,7 @@ func (h Methods) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}(r.Body)
 
 	// 요청 메서드(r.Method)를 보고 맵에서 요청 메서드에 해당하는 핸들러(handler)를 가져옴
-	if handler, ok := h[r.Method]; ok {
+	if handler, ok := h.handler(r.Method); ok {
 		// 혹시 모르게 발생할 패닉을 방지하기 위해, ServeHTTP 메서드는 요청 메서드에 해당하는 핸들러가 nil이 아닌지 확인
 		// 만약 nil이면, 500 Internal Server Error 반환
 		if handler == nil {
@@ -49,17 +49,53 @@ func (h Methods) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// 요청 메서드에 해당하는 핸들러를 반환
+// HEAD 핸들러가 없다면 GET 핸들러가 HEAD 요청을 처리하며, 이때 response body는 버림
+// 클라이언트는 GET 요청과 같은 헤더를 받으므로, 리소스를 내려받지 않고도 크기나 수정 시각을 확인할 수 있음
+func (h Methods) handler(method string) (http.Handler, bool) {
+	handler, ok := h[method]
+	if ok || method != http.MethodHead {
+		return handler, ok
+	}
+
+	handler, ok = h[http.MethodGet]
+	if !ok || handler == nil {
+		return handler, ok
+	}
+
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(headResponseWriter{w}, r)
+		},
+	), true
+}
+
 func (h Methods) allowedMethods() string {
-	a := make([]string, 0, len(h))
+	a := make([]string, 0, len(h)+1)
 
 	for k := range h {
 		a = append(a, k)
 	}
+	if _, ok := h[http.MethodHead]; !ok {
+		if _, ok := h[http.MethodGet]; ok {
+			a = append(a, http.MethodHead)
+		}
+	}
 	sort.Strings(a)
 
 	return strings.Join(a, ", ")
 }
 
+// response body를 버리는 http.ResponseWriter
+// net/http 서버도 HEAD 요청의 response body를 버리지만, httptest.ResponseRecorder 등 다른 구현을 위해 직접 버림
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func (w headResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
 // GET, POST, OPTIONS 메서드를 지원
 // 이 함수가 반환하는 핸들러는 그대로 handlers.DefaultHandler 함수에서 반환하는 핸들러로 교체 가능
 func DefaultMethodsHandler() http.Handler {
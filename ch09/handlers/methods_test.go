@@ -69,6 +69,24 @@ func TestMethodsHandler(t *testing.T) {
 	w = httptest.NewRecorder()
 	handler.ServeHTTP(w, r)
 	resp = w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %q", resp.Status)
+	}
+	// GET 핸들러가 HEAD 요청을 처리하지만 response body는 버려짐
+	if w.Body.Len() != 0 {
+		t.Fatalf("unexpected HEAD response body: %q", w.Body)
+	}
+
+	// GET 핸들러가 있으면 Allow 헤더에 HEAD가 포함됨
+	if expected := "GET, HEAD, POST"; allow != expected {
+		t.Fatalf("expected %q; actual %q", expected, allow)
+	}
+
+	// test DELETE
+	r = httptest.NewRequest(http.MethodDelete, "http://test", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	resp = w.Result()
 	if resp.StatusCode != http.StatusMethodNotAllowed {
 		t.Fatalf("unexpected status code: %q", resp.Status)
 	}
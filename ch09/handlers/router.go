@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Router는 HTTP 메서드와 경로 패턴을 함께 보고 요청을 핸들러로 라우팅하는 멀티플렉서
+// Methods 타입과 http.ServeMux를 합친 것으로, 경로 패턴마다 메서드별 핸들러를 등록함
+//
+// 경로 패턴은 "/"로 구분된 세그먼트로 이뤄짐
+//
+//	/users          정적 세그먼트는 그대로 일치해야 함
+//	/users/:id      ":"로 시작하는 세그먼트는 세그먼트 하나와 일치하며, Param(r, "id")로 값을 얻음
+//	/static/*path   "*"로 시작하는 마지막 세그먼트는 나머지 경로 전체와 일치
+//
+// 여러 패턴이 경로와 일치하면 앞쪽 세그먼트부터 비교해 정적 세그먼트, 매개변수, 와일드카드 순으로 구체적인 패턴을 우선함
+// 경로와 일치하는 패턴은 있지만 요청 메서드의 핸들러가 없다면, 일치하는 패턴의 모든 메서드를 Allow 헤더에 담아
+// 405 Method Not Allowed를 응답하고, 일치하는 패턴이 없다면 404 Not Found를 응답
+// GET 핸들러는 HEAD 요청도 처리하며, OPTIONS 요청에는 Allow 헤더와 함께 200 OK를 응답
+type Router struct {
+	// 일치하는 패턴이 없을 때 호출할 핸들러. nil이면 http.NotFound를 사용
+	NotFound http.Handler
+
+	routes []*route // 구체적인 패턴이 앞에 오도록 정렬됨
+}
+
+type route struct {
+	pattern  string
+	segments []segment
+	methods  Methods
+}
+
+type segmentKind int
+
+// 우선순위 순서
+const (
+	static segmentKind = iota
+	param
+	wildcard
+)
+
+type segment struct {
+	kind segmentKind
+	name string // 정적 세그먼트의 값 또는 매개변수의 이름
+}
+
+func NewRouter() *Router {
+	return new(Router)
+}
+
+// 메서드와 경로 패턴에 핸들러를 등록
+// 잘못된 패턴이나 이미 등록된 메서드와 패턴의 조합은 프로그래밍 에러이므로 http.ServeMux처럼 패닉을 일으킴
+func (rt *Router) Handle(method, pattern string, handler http.Handler) {
+	if handler == nil {
+		panic("handlers: nil handler for " + method + " " + pattern)
+	}
+	method = strings.ToUpper(method)
+
+	segments, err := parsePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, r := range rt.routes {
+		if !sameShape(r.segments, segments) {
+			continue
+		}
+		if r.pattern != pattern {
+			panic(fmt.Sprintf("handlers: pattern %q conflicts with %q",
+				pattern, r.pattern))
+		}
+		if _, ok := r.methods[method]; ok {
+			panic(fmt.Sprintf("handlers: multiple registrations for %s %s",
+				method, pattern))
+		}
+		r.methods[method] = handler
+		return
+	}
+
+	rt.routes = append(rt.routes, &route{
+		pattern:  pattern,
+		segments: segments,
+		methods:  Methods{method: handler},
+	})
+	sort.SliceStable(rt.routes, func(i, j int) bool {
+		return moreSpecific(rt.routes[i].segments, rt.routes[j].segments)
+	})
+}
+
+func (rt *Router) HandleFunc(method, pattern string,
+	handler func(http.ResponseWriter, *http.Request)) {
+	rt.Handle(method, pattern, http.HandlerFunc(handler))
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Methods 타입과 마찬가지로, 핸들러 대신 request body를 소비하고 닫음
+	defer func(r io.ReadCloser) {
+		_, _ = io.Copy(io.Discard, r)
+		_ = r.Close()
+	}(r.Body)
+
+	var (
+		matched bool
+		allowed = make(map[string]struct{})
+	)
+	for _, route := range rt.routes {
+		params, ok := route.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		matched = true
+
+		// Methods 타입과 같이 GET 핸들러가 HEAD 요청도 처리
+		if handler, ok := route.methods.handler(r.Method); ok {
+			if len(params) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), paramsKey{},
+					params))
+			}
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		for m := range route.methods {
+			allowed[m] = struct{}{}
+		}
+	}
+
+	if !matched {
+		if rt.NotFound != nil {
+			rt.NotFound.ServeHTTP(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	if _, ok := allowed[http.MethodGet]; ok {
+		allowed[http.MethodHead] = struct{}{}
+	}
+	allowed[http.MethodOptions] = struct{}{}
+	methods := make([]string, 0, len(allowed))
+	for m := range allowed {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+
+	w.Header().Add("Allow", strings.Join(methods, ", "))
+	if r.Method != http.MethodOptions {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+type paramsKey struct{}
+
+// 요청 경로에서 추출한 이름이 name인 경로 매개변수의 값을 반환
+// 와일드카드 매개변수의 값은 앞의 "/"를 제외한 나머지 경로
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+
+	return params[name]
+}
+
+func parsePattern(pattern string) ([]segment, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("handlers: pattern %q must begin with /",
+			pattern)
+	}
+
+	parts := strings.Split(pattern[1:], "/")
+	segments := make([]segment, 0, len(parts))
+	names := make(map[string]struct{})
+	for i, p := range parts {
+		s := segment{kind: static, name: p}
+		switch {
+		case strings.HasPrefix(p, ":"):
+			s = segment{kind: param, name: p[1:]}
+		case strings.HasPrefix(p, "*"):
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf(
+					"handlers: wildcard must be the last segment in %q", pattern)
+			}
+			s = segment{kind: wildcard, name: p[1:]}
+		}
+
+		if s.kind != static {
+			if s.name == "" && s.kind == param {
+				return nil, fmt.Errorf("handlers: unnamed parameter in %q",
+					pattern)
+			}
+			if _, ok := names[s.name]; ok && s.name != "" {
+				return nil, fmt.Errorf("handlers: duplicate parameter %q in %q",
+					s.name, pattern)
+			}
+			names[s.name] = struct{}{}
+		}
+		segments = append(segments, s)
+	}
+
+	return segments, nil
+}
+
+// 경로가 패턴과 일치하면 경로 매개변수를 반환
+func (rt *route) match(path string) (map[string]string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	path = path[1:]
+
+	var params map[string]string
+	for i, s := range rt.segments {
+		if s.kind == wildcard {
+			if s.name != "" {
+				if params == nil {
+					params = make(map[string]string)
+				}
+				params[s.name] = path
+			}
+			return params, true
+		}
+
+		part, rest, found := strings.Cut(path, "/")
+		// 마지막 세그먼트 뒤에 경로가 남았거나, 경로가 세그먼트보다 먼저 끝났다면 일치하지 않음
+		if found == (i == len(rt.segments)-1) {
+			return nil, false
+		}
+
+		switch s.kind {
+		case static:
+			if part != s.name {
+				return nil, false
+			}
+		case param:
+			if part == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[s.name] = part
+		}
+		path = rest
+	}
+
+	return params, true
+}
+
+// a가 b보다 구체적인 패턴인지 여부
+func moreSpecific(a, b []segment) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].kind != b[i].kind {
+			return a[i].kind < b[i].kind
+		}
+	}
+
+	return len(a) > len(b)
+}
+
+// 매개변수 이름을 제외하고 같은 경로와 일치하는 패턴인지 여부
+func sameShape(a, b []segment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].kind != b[i].kind ||
+			(a[i].kind == static && a[i].name != b[i].name) {
+			return false
+		}
+	}
+
+	return true
+}
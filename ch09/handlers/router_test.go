@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	rt := NewRouter()
+	reply := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "%s id=%s path=%s", name, Param(r, "id"),
+				Param(r, "path"))
+		}
+	}
+	rt.Handle(http.MethodGet, "/", reply("index"))
+	rt.Handle(http.MethodGet, "/users", reply("list"))
+	rt.Handle(http.MethodPost, "/users", reply("create"))
+	rt.Handle(http.MethodGet, "/users/:id", reply("show"))
+	rt.Handle(http.MethodDelete, "/users/:id", reply("delete"))
+	rt.Handle(http.MethodGet, "/users/new", reply("new"))
+	rt.Handle(http.MethodGet, "/static/*path", reply("static"))
+
+	testCases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+		allow  string
+	}{
+		{http.MethodGet, "/", http.StatusOK, "index id= path=", ""},
+		{http.MethodGet, "/users", http.StatusOK, "list id= path=", ""},
+		{http.MethodPost, "/users", http.StatusOK, "create id= path=", ""},
+		{http.MethodGet, "/users/42", http.StatusOK, "show id=42 path=", ""},
+		// 정적 세그먼트가 매개변수보다 우선
+		{http.MethodGet, "/users/new", http.StatusOK, "new id= path=", ""},
+		// 구체적인 패턴에 메서드가 없으면 덜 구체적인 패턴으로 넘어감
+		{http.MethodDelete, "/users/new", http.StatusOK, "delete id=new path=",
+			""},
+		{http.MethodGet, "/static/css/style.css", http.StatusOK,
+			"static id= path=css/style.css", ""},
+		{http.MethodGet, "/static/", http.StatusOK, "static id= path=", ""},
+		// HEAD 요청은 GET 핸들러가 처리하지만 response body는 버려짐
+		{http.MethodHead, "/users/42", http.StatusOK, "", ""},
+		{http.MethodPut, "/users/42", http.StatusMethodNotAllowed, "",
+			"DELETE, GET, HEAD, OPTIONS"},
+		{http.MethodDelete, "/users", http.StatusMethodNotAllowed, "",
+			"GET, HEAD, OPTIONS, POST"},
+		{http.MethodOptions, "/users", http.StatusOK, "",
+			"GET, HEAD, OPTIONS, POST"},
+		{http.MethodGet, "/users/42/edit", http.StatusNotFound, "", ""},
+		{http.MethodGet, "/users/", http.StatusNotFound, "", ""},
+		{http.MethodGet, "/static", http.StatusNotFound, "", ""},
+		{http.MethodGet, "/missing", http.StatusNotFound, "", ""},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(c.method, "http://test"+c.path, nil)
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, resp.StatusCode)
+			continue
+		}
+		if actual := resp.Header.Get("Allow"); actual != c.allow {
+			t.Errorf("%d: expected Allow %q; actual %q", i, c.allow, actual)
+		}
+		if c.code != http.StatusOK {
+			continue
+		}
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := string(b); actual != c.body {
+			t.Errorf("%d: expected %q; actual %q", i, c.body, actual)
+		}
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	rt := &Router{NotFound: http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		},
+	)}
+	rt.HandleFunc(http.MethodGet, "/", func(http.ResponseWriter, *http.Request) {})
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/missing", nil)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+
+	if actual := w.Result().StatusCode; actual != http.StatusTeapot {
+		t.Fatalf("expected %d; actual %d", http.StatusTeapot, actual)
+	}
+}
+
+func TestRouterPanics(t *testing.T) {
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	testCases := []struct {
+		method  string
+		pattern string
+	}{
+		{http.MethodGet, "users"},
+		{http.MethodGet, "/files/*path/edit"},
+		{http.MethodGet, "/users/:"},
+		{http.MethodGet, "/users/:id/:id"},
+		// 이미 등록된 패턴
+		{http.MethodGet, "/users/:id"},
+		// 매개변수 이름만 다른 패턴
+		{http.MethodPost, "/users/:name"},
+	}
+
+	for i, c := range testCases {
+		func() {
+			rt := NewRouter()
+			rt.Handle(http.MethodGet, "/users/:id", noop)
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expected a panic for %s %s", i, c.method,
+						c.pattern)
+				}
+			}()
+			rt.Handle(c.method, c.pattern, noop)
+		}()
+	}
+}
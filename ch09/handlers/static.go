@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 파일 이름에 내용의 해시가 포함된 자산(예: app.3f2a9c1b.css, logo-5d41402abc4b.svg)
+// 내용이 바뀌면 이름도 바뀌므로 클라이언트가 오랫동안 캐시해도 안전함
+var fingerprinted = regexp.MustCompile(`[.-][0-9a-fA-F]{8,}\.[^./]+$`)
+
+type encoding struct {
+	encoding  string // Content-Encoding 헤더의 값
+	extension string
+}
+
+// 미리 압축된 파일의 확장자. 앞쪽일수록 우선함
+var precompressed = []encoding{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// Static은 파일 시스템의 정적 파일을 제공하는 http.Handler
+// http.FileServer와 달리 다음과 같이 동작함
+//
+//   - 디렉터리 목록을 보여주지 않음. 디렉터리에 index.html 파일이 있으면 그 파일을 제공하고, 없으면 404 Not Found를 응답
+//   - "."으로 시작하는 숨김 파일과 디렉터리는 존재 여부와 상관없이 404 Not Found를 응답
+//   - 클라이언트가 Accept-Encoding 헤더로 허용하면, 같은 이름에 .br 또는 .gz 확장자가 붙은 미리 압축된 파일을 제공
+//   - 이름에 해시가 포함된 파일은 1년간 캐시하도록, 그 외의 파일은 매번 재검증하도록 Cache-Control 헤더를 설정
+//   - Range 요청과 If-Modified-Since 등의 조건부 요청은 http.ServeContent 함수로 처리
+//
+// embed.FS처럼 파일의 수정 시각이 없는 파일 시스템은 내용의 해시로 ETag를 계산해 조건부 요청에 사용함
+type Static struct {
+	fsys fs.FS
+
+	mu    sync.Mutex
+	etags map[string]string // 수정 시각이 없는 파일의 ETag
+}
+
+// fsys의 파일을 제공하는 핸들러를 반환. 디스크의 디렉터리는 os.DirFS 함수로, 임베드한 파일은 embed.FS로 전달
+// 요청 경로는 fsys의 루트를 기준으로 하므로, 접두사가 있는 경로에 연결할 때는 http.StripPrefix 함수로 감쌀 것
+func NewStatic(fsys fs.FS) *Static {
+	return &Static{fsys: fsys, etags: make(map[string]string)}
+}
+
+func (s *Static) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.ServeFile(w, r, r.URL.Path)
+}
+
+// 요청 경로 대신 name 파일을 제공
+func (s *Static) ServeFile(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// path.Clean 함수로 ".."를 제거해 루트 밖의 파일에 접근할 수 없도록 함
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+	if hidden(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, info, err := s.open(name)
+	if err == nil && info.IsDir() {
+		_ = f.Close()
+		name = path.Join(name, "index.html")
+		f, info, err = s.open(name)
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			http.NotFound(w, r)
+		} else {
+			http.Error(w, "Internal server error",
+				http.StatusInternalServerError)
+		}
+		return
+	}
+	if info.IsDir() {
+		_ = f.Close()
+		http.NotFound(w, r)
+		return
+	}
+
+	h := w.Header()
+	// 압축된 파일의 확장자가 아닌 원래 파일의 확장자로 형식을 결정
+	ct := mime.TypeByExtension(path.Ext(name))
+	if ct != "" {
+		h.Set("Content-Type", ct)
+	}
+	if fingerprinted.MatchString(name) {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+
+	// 형식을 알 수 없는 파일은 http.ServeContent 함수가 내용으로 형식을 추측하므로, 압축된 파일을 제공하지 않음
+	// 같은 URL이라도 Accept-Encoding 헤더에 따라 응답이 달라질 수 있음
+	modTime := info.ModTime()
+	if ct != "" {
+		h.Add("Vary", "Accept-Encoding")
+		if cf, cinfo, p := s.openPrecompressed(r, name); cf != nil {
+			_ = f.Close()
+			f, modTime = cf, cinfo.ModTime()
+			h.Set("Content-Encoding", p.encoding)
+			name += p.extension
+		}
+	}
+	defer func() { _ = f.Close() }()
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, "Internal server error",
+				http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(b)
+	}
+
+	if modTime.IsZero() {
+		etag, err := s.etag(name, content)
+		if err != nil {
+			http.Error(w, "Internal server error",
+				http.StatusInternalServerError)
+			return
+		}
+		h.Set("ETag", etag)
+	}
+
+	http.ServeContent(w, r, info.Name(), modTime, content)
+}
+
+func (s *Static) open(name string) (fs.File, fs.FileInfo, error) {
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+
+	return f, info, nil
+}
+
+// 클라이언트가 허용하는 인코딩으로 미리 압축된 파일을 찾아 반환. 없으면 nil을 반환
+func (s *Static) openPrecompressed(r *http.Request, name string) (fs.File,
+	fs.FileInfo, encoding) {
+	accepted := acceptedEncodings(r)
+	for _, p := range precompressed {
+		if _, ok := accepted[p.encoding]; !ok {
+			continue
+		}
+
+		f, info, err := s.open(name + p.extension)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			_ = f.Close()
+			continue
+		}
+
+		return f, info, p
+	}
+
+	return nil, nil, encoding{}
+}
+
+// 수정 시각이 없는 파일의 ETag를 내용의 해시로 계산
+// 이런 파일 시스템(embed.FS)은 내용이 바뀌지 않으므로 파일 이름별로 캐시함
+func (s *Static) etag(name string, content io.ReadSeeker) (string, error) {
+	s.mu.Lock()
+	etag, ok := s.etags[name]
+	s.mu.Unlock()
+	if ok {
+		return etag, nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag = `"` + base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:18]) + `"`
+
+	s.mu.Lock()
+	s.etags[name] = etag
+	s.mu.Unlock()
+
+	return etag, nil
+}
+
+// 경로의 세그먼트 중 하나라도 "."으로 시작하는지 확인
+func hidden(name string) bool {
+	if name == "." {
+		return false
+	}
+	for _, p := range strings.Split(name, "/") {
+		if strings.HasPrefix(p, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Accept-Encoding 헤더에서 q=0이 아닌 인코딩의 집합을 반환
+func acceptedEncodings(r *http.Request) map[string]struct{} {
+	accepted := make(map[string]struct{})
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for _, enc := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+			q := 1.0
+			for _, p := range strings.Split(params, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+				if ok && strings.EqualFold(k, "q") {
+					q, _ = strconv.ParseFloat(v, 64)
+				}
+			}
+			if q > 0 {
+				accepted[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+			}
+		}
+	}
+
+	return accepted
+}
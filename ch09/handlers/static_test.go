@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestStatic(t *testing.T) {
+	modified := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler := http.StripPrefix("/static/", NewStatic(fstest.MapFS{
+		"index.html":          {Data: []byte("<h1>index</h1>"), ModTime: modified},
+		"style.css":           {Data: []byte("body{}"), ModTime: modified},
+		"style.css.gz":        {Data: []byte("gzipped"), ModTime: modified},
+		"style.css.br":        {Data: []byte("brotli"), ModTime: modified},
+		"app.3f2a9c1b.js":     {Data: []byte("js"), ModTime: modified},
+		"docs/readme.txt":     {Data: []byte("0123456789"), ModTime: modified},
+		"empty/.keep":         {Data: nil, ModTime: modified},
+		".secret":             {Data: []byte("secret"), ModTime: modified},
+		".dir/secret":         {Data: []byte("secret"), ModTime: modified},
+		"nomime":              {Data: []byte("plain"), ModTime: modified},
+		"nomime.gz":           {Data: []byte("gzipped"), ModTime: modified},
+		"images/sub/logo.svg": {Data: []byte("<svg/>"), ModTime: modified},
+	}))
+
+	testCases := []struct {
+		path     string
+		header   map[string]string
+		code     int
+		body     string
+		encoding string
+		cache    string
+	}{
+		{"/static/style.css", nil, http.StatusOK, "body{}", "", "no-cache"},
+		{"/static/style.css", map[string]string{"Accept-Encoding": "gzip"},
+			http.StatusOK, "gzipped", "gzip", "no-cache"},
+		{"/static/style.css", map[string]string{"Accept-Encoding": "gzip, br"},
+			http.StatusOK, "brotli", "br", "no-cache"},
+		{"/static/style.css", map[string]string{"Accept-Encoding": "br;q=0, gzip"},
+			http.StatusOK, "gzipped", "gzip", "no-cache"},
+		// 형식을 알 수 없는 파일은 압축된 파일을 제공하지 않음
+		{"/static/nomime", map[string]string{"Accept-Encoding": "gzip"},
+			http.StatusOK, "plain", "", "no-cache"},
+		{"/static/app.3f2a9c1b.js", nil, http.StatusOK, "js", "",
+			"public, max-age=31536000, immutable"},
+		{"/static/docs/readme.txt", map[string]string{"Range": "bytes=2-4"},
+			http.StatusPartialContent, "234", "", "no-cache"},
+		{"/static/docs/readme.txt", map[string]string{
+			"If-Modified-Since": modified.Format(http.TimeFormat)},
+			http.StatusNotModified, "", "", "no-cache"},
+		// 디렉터리는 index.html 파일을 제공하고, 목록은 보여주지 않음
+		{"/static/", nil, http.StatusOK, "<h1>index</h1>", "", "no-cache"},
+		{"/static/docs/", nil, http.StatusNotFound, "", "", ""},
+		{"/static/empty", nil, http.StatusNotFound, "", "", ""},
+		{"/static/.secret", nil, http.StatusNotFound, "", "", ""},
+		{"/static/.dir/secret", nil, http.StatusNotFound, "", "", ""},
+		{"/static/docs/../.secret", nil, http.StatusNotFound, "", "", ""},
+		{"/static/../../etc/passwd", nil, http.StatusNotFound, "", "", ""},
+		{"/static/missing.css", nil, http.StatusNotFound, "", "", ""},
+		{"/static/images/sub/logo.svg", nil, http.StatusOK, "<svg/>", "",
+			"no-cache"},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://test"+c.path, nil)
+		for k, v := range c.header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != c.code {
+			t.Errorf("%d: %s: expected %d; actual %d", i, c.path, c.code,
+				resp.StatusCode)
+			continue
+		}
+		if c.code != http.StatusOK && c.code != http.StatusPartialContent {
+			continue
+		}
+		if actual := w.Body.String(); actual != c.body {
+			t.Errorf("%d: expected %q; actual %q", i, c.body, actual)
+		}
+		if actual := resp.Header.Get("Content-Encoding"); actual != c.encoding {
+			t.Errorf("%d: expected encoding %q; actual %q", i, c.encoding, actual)
+		}
+		if actual := resp.Header.Get("Cache-Control"); actual != c.cache {
+			t.Errorf("%d: expected Cache-Control %q; actual %q", i, c.cache,
+				actual)
+		}
+	}
+}
+
+func TestStaticContentType(t *testing.T) {
+	handler := NewStatic(fstest.MapFS{
+		"style.css":    {Data: []byte("body{}")},
+		"style.css.gz": {Data: []byte("\x1f\x8b")},
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/style.css", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	// 압축된 파일도 원래 파일의 형식으로 응답
+	if ct := w.Result().Header.Get("Content-Type"); ct != "text/css; charset=utf-8" {
+		t.Fatalf("expected %q; actual %q", "text/css; charset=utf-8", ct)
+	}
+}
+
+// 수정 시각이 없는 파일 시스템(embed.FS)은 내용의 해시로 ETag를 계산
+func TestStaticETag(t *testing.T) {
+	handler := NewStatic(fstest.MapFS{"a.txt": {Data: []byte("hello")}})
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/a.txt", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	resp := w.Result()
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+	if resp.Header.Get("Last-Modified") != "" {
+		t.Error("unexpected Last-Modified")
+	}
+	if w.Body.String() != "hello" {
+		t.Fatalf("expected %q; actual %q", "hello", w.Body)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "http://test/a.txt", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if actual := w.Result().StatusCode; actual != http.StatusNotModified {
+		t.Fatalf("expected %d; actual %d", http.StatusNotModified, actual)
+	}
+}
+
+func TestStaticDir(t *testing.T) {
+	handler := http.StripPrefix("/static/", NewStatic(os.DirFS("../files")))
+
+	testCases := []struct {
+		path string
+		code int
+	}{
+		{"http://test/static/sage.svg", http.StatusOK},
+		{"http://test/static/.secret", http.StatusNotFound},
+		{"http://test/static/.dir/secret", http.StatusNotFound},
+		{"http://test/static/", http.StatusOK},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodGet, c.path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if actual := w.Result().StatusCode; actual != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, actual)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 업로드된 파일 하나에 대한 정보
+type UploadedFile struct {
+	Field       string `json:"field"`
+	FileName    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// 업로드 핸들러의 응답 body
+type UploadResult struct {
+	Fields map[string]string `json:"fields"`
+	Files  []UploadedFile    `json:"files"`
+}
+
+// 폼 필드 값의 최대 크기
+const maxFieldSize = 1 << 20
+
+// 멀티파트 POST 요청의 파일을 dir 디렉터리에 저장하는 핸들러
+// r.ParseMultipartForm은 파트를 메모리(일정 크기 이상은 임시 파일)에 모두 읽어 들인 후에야 핸들러에게 넘겨주지만,
+// 이 핸들러는 r.MultipartReader로 파트를 하나씩 읽으면서 바로 디스크에 씀
+// maxBytes가 0보다 크면 요청 body 전체의 크기를 제한하며, 초과하면 413 Request Entity Too Large를 응답
+// 같은 이름의 파일이 이미 있으면 덮어쓰지 않고 409 Conflict를 응답하며, 요청이 실패하면 저장한 파일을 모두 지움
+// 성공하면 저장한 파일과 폼 필드의 목록을 JSON으로 응답
+func Upload(dir string, maxBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if maxBytes > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result := UploadResult{Fields: make(map[string]string)}
+		var saved []string
+		fail := func(err error, code int) {
+			for _, path := range saved {
+				_ = os.Remove(path)
+			}
+			var mErr *http.MaxBytesError
+			if errors.As(err, &mErr) {
+				code = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), code)
+		}
+
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				fail(err, http.StatusBadRequest)
+				return
+			}
+
+			// 파일이 아닌 파트는 폼 필드로 처리
+			if part.FileName() == "" {
+				b, err := io.ReadAll(io.LimitReader(part, maxFieldSize+1))
+				if err == nil && len(b) > maxFieldSize {
+					err = fmt.Errorf("field %q too large", part.FormName())
+				}
+				if err != nil {
+					fail(err, http.StatusBadRequest)
+					return
+				}
+				result.Fields[part.FormName()] = string(b)
+				continue
+			}
+
+			name, err := safeFileName(part.FileName())
+			if err != nil {
+				fail(err, http.StatusBadRequest)
+				return
+			}
+			path := filepath.Join(dir, name)
+
+			n, code, err := saveFile(path, part)
+			if err != nil {
+				fail(err, code)
+				return
+			}
+			saved = append(saved, path)
+
+			result.Files = append(result.Files, UploadedFile{
+				Field:       part.FormName(),
+				FileName:    name,
+				ContentType: part.Header.Get("Content-Type"),
+				Size:        n,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
+
+// 파트의 내용을 새로운 파일로 저장하고, 쓴 바이트 수를 반환
+// 실패하면 만들던 파일을 지우고, 응답할 상태 코드도 함께 반환
+// 요청 body의 크기 제한을 넘으면 413, body를 읽다 실패하면 400, 디스크에 쓰거나 닫다 실패하면 500
+func saveFile(path string, r io.Reader) (int64, int, error) {
+	// O_EXCL: 파일이 이미 존재하면 실패
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return 0, http.StatusConflict,
+				fmt.Errorf("%s already exists", filepath.Base(path))
+		}
+		return 0, http.StatusInternalServerError, err
+	}
+
+	src := &readErrReader{Reader: r}
+	n, err := io.Copy(f, src)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+
+		var mErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &mErr):
+			return n, http.StatusRequestEntityTooLarge, err
+		case src.err != nil:
+			// 요청 body를 읽다 실패했다면 클라이언트의 문제
+			return n, http.StatusBadRequest, err
+		default:
+			return n, http.StatusInternalServerError, err
+		}
+	}
+
+	return n, http.StatusCreated, nil
+}
+
+// io.Copy의 에러가 읽기와 쓰기 중 어디에서 발생했는지 구분하기 위해 읽기 에러를 기록
+type readErrReader struct {
+	io.Reader
+	err error
+}
+
+func (r *readErrReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+
+	return n, err
+}
+
+// 클라이언트가 보낸 파일 이름에서 디렉터리를 제거
+// ../../etc/passwd 같은 이름으로 dir 밖에 파일을 쓰거나, 숨김 파일을 만드는 것을 막음
+func safeFileName(name string) (string, error) {
+	// 윈도우 클라이언트는 역슬래시를 구분자로 보낼 수 있음
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid file name %q", name)
+	}
+
+	return name, nil
+}
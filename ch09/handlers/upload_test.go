@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func multipartBody(t *testing.T, files map[string]string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	if err := w.WriteField("description", "test upload"); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		fw, err := w.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return body, w.FormDataContentType()
+}
+
+func TestUpload(t *testing.T) {
+	dir := t.TempDir()
+	handler := Upload(dir, 1024)
+
+	body, ct := multipartBody(t, map[string]string{"hello.txt": "Hello, world!"})
+	r := httptest.NewRequest(http.MethodPost, "http://test/upload", body)
+	r.Header.Set("Content-Type", ct)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("unexpected status code %d: %s", w.Code, w.Body)
+	}
+
+	var result UploadResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Fields["description"] != "test upload" || len(result.Files) != 1 ||
+		result.Files[0].Size != 13 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "hello.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Hello, world!"; string(b) != expected {
+		t.Fatalf("expected %q; actual %q", expected, b)
+	}
+
+	// 같은 이름의 파일은 덮어쓰지 않음
+	body, ct = multipartBody(t, map[string]string{"hello.txt": "overwritten"})
+	r = httptest.NewRequest(http.MethodPost, "http://test/upload", body)
+	r.Header.Set("Content-Type", ct)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d; actual %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestUploadErrors(t *testing.T) {
+	dir := t.TempDir()
+	handler := Upload(dir, 1024)
+
+	for _, c := range []struct {
+		name   string
+		method string
+		files  map[string]string
+		code   int
+	}{
+		{"method", http.MethodGet, nil, http.StatusMethodNotAllowed},
+		{"too large", http.MethodPost,
+			map[string]string{"big.bin": string(make([]byte, 2048))},
+			http.StatusRequestEntityTooLarge},
+		{"hidden file", http.MethodPost, map[string]string{".secret": "x"},
+			http.StatusBadRequest},
+	} {
+		body, ct := multipartBody(t, c.files)
+		r := httptest.NewRequest(c.method, "http://test/upload", body)
+		r.Header.Set("Content-Type", ct)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != c.code {
+			t.Errorf("%s: expected status %d; actual %d", c.name, c.code, w.Code)
+		}
+	}
+
+	// 실패한 요청의 파일은 남지 않아야 함
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no files; actual %d", len(entries))
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, c := range []struct {
+		name string
+		r    io.Reader
+		code int
+	}{
+		{"ok", bytes.NewReader([]byte("ok")), http.StatusCreated},
+		{"too large", io.MultiReader(bytes.NewReader([]byte("partial")),
+			iotest.ErrReader(&http.MaxBytesError{Limit: 7})),
+			http.StatusRequestEntityTooLarge},
+		{"read error", io.MultiReader(bytes.NewReader([]byte("partial")),
+			iotest.ErrReader(errors.New("unexpected EOF"))),
+			http.StatusBadRequest},
+	} {
+		path := filepath.Join(dir, c.name)
+		_, code, err := saveFile(path, c.r)
+		if code != c.code {
+			t.Errorf("%s: expected status %d; actual %d (%v)", c.name, c.code,
+				code, err)
+		}
+
+		// 실패하면 일부만 쓴 파일이 남지 않아야 함
+		_, err = os.Stat(path)
+		if exists := err == nil; exists != (c.code == http.StatusCreated) {
+			t.Errorf("%s: unexpected file state: %v", c.name, err)
+		}
+	}
+
+	// 파일을 만들지 못한 경우는 서버의 문제
+	_, code, _ := saveFile(filepath.Join(dir, "missing", "x"),
+		bytes.NewReader(nil))
+	if code != http.StatusInternalServerError {
+		t.Errorf("expected status %d; actual %d", http.StatusInternalServerError,
+			code)
+	}
+}
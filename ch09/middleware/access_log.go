@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 요청마다 응답이 끝난 후 구조화된 접근 로그를 기록하는 미들웨어
+// RequestID 미들웨어 안쪽에 두면 로그에 요청 ID가 포함됨
+func AccessLog(log *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				start := time.Now()
+				rec := &responseRecorder{ResponseWriter: w}
+
+				// 핸들러가 패닉을 일으켜도 로그를 남김
+				defer func() {
+					fields := []zap.Field{
+						zap.String("method", r.Method),
+						zap.String("path", r.URL.Path),
+						zap.String("proto", r.Proto),
+						zap.Int("status", rec.Status()),
+						zap.Int64("size", rec.size),
+						zap.Duration("duration", time.Since(start)),
+						zap.String("remote", r.RemoteAddr),
+						zap.String("user_agent", r.UserAgent()),
+					}
+					if id := RequestIDFromContext(r.Context()); id != "" {
+						fields = append(fields, zap.String("request_id", id))
+					}
+					log.Info("request", fields...)
+				}()
+
+				next.ServeHTTP(rec, r)
+			},
+		)
+	}
+}
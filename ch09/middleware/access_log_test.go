@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	handler := Chain(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		}),
+		RequestID, AccessLog(zap.New(core)),
+	)
+
+	r := httptest.NewRequest(http.MethodPost, "http://test/items", nil)
+	r.Header.Set(RequestIDHeader, "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry; actual %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	for k, expected := range map[string]any{
+		"method":     http.MethodPost,
+		"path":       "/items",
+		"status":     int64(http.StatusCreated),
+		"size":       int64(len("created")),
+		"request_id": "abc",
+	} {
+		if actual := fields[k]; actual != expected {
+			t.Errorf("%s: expected %v; actual %v", k, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import "net/http"
+
+// request body의 크기를 최대 n 바이트로 제한하는 미들웨어
+// Content-Length가 제한보다 크면 핸들러를 호출하지 않고 413 Request Entity Too Large를 응답
+// 길이를 알 수 없다면 http.MaxBytesReader로 body를 감싸 제한을 넘는 순간 읽기가 실패하도록 함
+// 이때 핸들러는 errors.As로 *http.MaxBytesError를 확인해 413을 응답할 수 있음
+func MaxBodySize(n int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.ContentLength > n {
+					// 남은 body를 읽지 않도록 응답 후 연결을 닫음
+					w.Header().Set("Connection", "close")
+					http.Error(w, "Request entity too large",
+						http.StatusRequestEntityTooLarge)
+					return
+				}
+
+				r.Body = http.MaxBytesReader(w, r.Body, n)
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
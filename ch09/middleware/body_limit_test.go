@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	handler := MaxBodySize(8)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, err := io.ReadAll(r.Body)
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				http.Error(w, "Too large", http.StatusRequestEntityTooLarge)
+			}
+		},
+	))
+
+	testCases := []struct {
+		body    string
+		unknown bool // Content-Length를 알 수 없는 경우
+		code    int
+	}{
+		{"small", false, http.StatusOK},
+		{"12345678", false, http.StatusOK},
+		{"123456789", false, http.StatusRequestEntityTooLarge},
+		{"123456789", true, http.StatusRequestEntityTooLarge},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodPost, "http://test/",
+			strings.NewReader(c.body))
+		if c.unknown {
+			r.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if actual := w.Result().StatusCode; actual != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, actual)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import "net/http"
+
+// http.Handler를 받아 기능을 덧붙인 http.Handler를 반환하는 미들웨어
+type Middleware func(http.Handler) http.Handler
+
+// 핸들러를 미들웨어로 차례로 감싼 핸들러를 반환
+// 첫 번째 미들웨어가 가장 바깥에 위치해 요청을 가장 먼저 받음
+//
+//	Chain(h, RequestID, Recover(log)) == RequestID(Recover(log)(h))
+func Chain(h http.Handler, m ...Middleware) http.Handler {
+	for i := len(m) - 1; i >= 0; i-- {
+		h = m[i](h)
+	}
+
+	return h
+}
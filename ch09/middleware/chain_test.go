@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					order = append(order, name)
+					next.ServeHTTP(w, r)
+				},
+			)
+		}
+	}
+
+	handler := Chain(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, "handler")
+		}),
+		mark("first"), mark("second"), mark("third"),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	expected := "first second third handler"
+	if actual := strings.Join(order, " "); actual != expected {
+		t.Fatalf("expected %q; actual %q", expected, actual)
+	}
+}
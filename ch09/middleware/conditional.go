@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 핸들러의 응답을 버퍼링해 강한 ETag를 계산하고, 조건부 요청에 304 Not Modified로 응답하는 미들웨어
+// GET과 HEAD 요청의 200 OK 응답만 처리하며, 핸들러가 ETag 헤더를 설정했다면 계산하지 않고 그 값을 사용함
+//
+// If-None-Match 헤더가 있으면 ETag를 약한 비교로 확인하고, 없으면 If-Modified-Since 헤더를
+// 핸들러가 설정한 Last-Modified 헤더와 비교함
+//
+// response body가 maxBuffer 바이트를 넘거나 핸들러가 Flush 메서드를 호출하면 버퍼링을 멈추고 그대로 응답
+// HEAD 요청은 GET 요청으로 바꿔 핸들러를 호출하므로 GET 응답과 같은 ETag를 얻으며, response body는 버림
+func Conditional(maxBuffer int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet && r.Method != http.MethodHead {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				cw := &conditionalWriter{
+					ResponseWriter: w,
+					r:              r,
+					max:            maxBuffer,
+					head:           r.Method == http.MethodHead,
+				}
+				if cw.head {
+					r = r.Clone(r.Context())
+					r.Method = http.MethodGet
+				}
+
+				next.ServeHTTP(cw, r)
+				cw.finish()
+			},
+		)
+	}
+}
+
+type conditionalWriter struct {
+	http.ResponseWriter
+	r    *http.Request // 원래의 요청
+	max  int
+	head bool
+
+	status    int
+	buf       bytes.Buffer
+	streaming bool // 버퍼링을 멈추고 그대로 응답하는 중인지 여부
+}
+
+func (c *conditionalWriter) WriteHeader(status int) {
+	// 103 Early Hints 같은 정보성 응답은 그대로 전달
+	if status >= 100 && status < 200 {
+		c.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if c.status != 0 {
+		return
+	}
+
+	c.status = status
+	// 200 OK가 아닌 응답은 조건부 요청의 대상이 아니므로 버퍼링하지 않음
+	if status != http.StatusOK {
+		c.stream()
+	}
+}
+
+func (c *conditionalWriter) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.streaming {
+		return c.write(p)
+	}
+
+	if c.buf.Len()+len(p) > c.max {
+		c.stream()
+		return c.write(p)
+	}
+
+	return c.buf.Write(p)
+}
+
+func (c *conditionalWriter) Flush() {
+	if c.status == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+	c.stream()
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *conditionalWriter) Unwrap() http.ResponseWriter { return c.ResponseWriter }
+
+func (c *conditionalWriter) write(p []byte) (int, error) {
+	if c.head {
+		return len(p), nil
+	}
+
+	return c.ResponseWriter.Write(p)
+}
+
+// 버퍼링을 멈추고 지금까지 버퍼링한 응답을 보냄
+func (c *conditionalWriter) stream() {
+	if c.streaming {
+		return
+	}
+	c.streaming = true
+
+	c.ResponseWriter.WriteHeader(c.status)
+	if c.buf.Len() > 0 {
+		_, _ = c.write(c.buf.Bytes())
+		c.buf.Reset()
+	}
+}
+
+// 핸들러가 반환된 후, 버퍼링한 응답으로 조건부 요청을 평가해 응답
+func (c *conditionalWriter) finish() {
+	if c.streaming || c.status == 0 {
+		return
+	}
+
+	h := c.Header()
+	if h.Get("ETag") == "" {
+		sum := sha256.Sum256(c.buf.Bytes())
+		h.Set("ETag", `"`+base64.RawURLEncoding.EncodeToString(sum[:18])+`"`)
+	}
+
+	if notModified(c.r, h) {
+		// 304 응답에는 body와 body를 설명하는 헤더가 없어야 함
+		for _, k := range []string{"Content-Type", "Content-Length",
+			"Content-Encoding", "Content-Range"} {
+			h.Del(k)
+		}
+		c.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// 전체 body의 크기를 알고 있으므로, HEAD 요청에도 GET 요청과 같은 Content-Length를 응답
+	if h.Get("Content-Length") == "" {
+		h.Set("Content-Length", strconv.Itoa(c.buf.Len()))
+	}
+	c.stream()
+}
+
+// RFC 9110 13.2.2절의 순서에 따라 조건부 GET 요청을 평가
+func notModified(r *http.Request, h http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, h.Get("ETag"))
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	lm := h.Get("Last-Modified")
+	if ims == "" || lm == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lm)
+	if err != nil {
+		return false
+	}
+
+	// HTTP 날짜는 초 단위이므로 초 단위로 비교
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// If-None-Match 헤더의 ETag 목록 중 하나라도 etag와 약한 비교로 일치하는지 확인
+func etagMatch(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return etag != ""
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, v := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(v), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
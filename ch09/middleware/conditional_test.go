@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditional(t *testing.T) {
+	modified := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler := Conditional(64)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// HEAD 요청은 GET 요청으로 바뀌어 전달됨
+			if r.Method == http.MethodHead {
+				t.Error("unexpected HEAD request")
+			}
+			switch r.URL.Path {
+			case "/modified":
+				w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+			case "/etag":
+				w.Header().Set("ETag", `"v1"`)
+			case "/large":
+				_, _ = w.Write([]byte(strings.Repeat("x", 65)))
+				return
+			case "/missing":
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte("Hello, friend!"))
+		},
+	))
+
+	// 조건 없이 요청해 계산된 ETag를 얻음
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	etag := w.Result().Header.Get("ETag")
+	if !strings.HasPrefix(etag, `"`) {
+		t.Fatalf("expected a strong ETag; actual %q", etag)
+	}
+
+	since := modified.Format(http.TimeFormat)
+	before := modified.Add(-time.Second).Format(http.TimeFormat)
+
+	testCases := []struct {
+		method string
+		path   string
+		header map[string]string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/", nil, http.StatusOK, "Hello, friend!"},
+		{http.MethodGet, "/", map[string]string{"If-None-Match": etag},
+			http.StatusNotModified, ""},
+		{http.MethodGet, "/", map[string]string{"If-None-Match": `"a", W/` + etag},
+			http.StatusNotModified, ""},
+		{http.MethodGet, "/", map[string]string{"If-None-Match": "*"},
+			http.StatusNotModified, ""},
+		{http.MethodGet, "/", map[string]string{"If-None-Match": `"other"`},
+			http.StatusOK, "Hello, friend!"},
+		// HEAD 요청도 GET 요청과 같은 ETag로 평가
+		{http.MethodHead, "/", map[string]string{"If-None-Match": etag},
+			http.StatusNotModified, ""},
+		{http.MethodHead, "/", nil, http.StatusOK, ""},
+		{http.MethodGet, "/etag", map[string]string{"If-None-Match": `"v1"`},
+			http.StatusNotModified, ""},
+		{http.MethodGet, "/modified",
+			map[string]string{"If-Modified-Since": since},
+			http.StatusNotModified, ""},
+		{http.MethodGet, "/modified",
+			map[string]string{"If-Modified-Since": before},
+			http.StatusOK, "Hello, friend!"},
+		// If-None-Match 헤더가 있으면 If-Modified-Since 헤더는 무시
+		{http.MethodGet, "/modified", map[string]string{
+			"If-None-Match": `"other"`, "If-Modified-Since": since},
+			http.StatusOK, "Hello, friend!"},
+		{http.MethodGet, "/missing", map[string]string{"If-None-Match": "*"},
+			http.StatusNotFound, "404 page not found\n"},
+		{http.MethodPost, "/", map[string]string{"If-None-Match": etag},
+			http.StatusOK, "Hello, friend!"},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(c.method, "http://test"+c.path, nil)
+		for k, v := range c.header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, resp.StatusCode)
+			continue
+		}
+		if actual := w.Body.String(); actual != c.body {
+			t.Errorf("%d: expected %q; actual %q", i, c.body, actual)
+		}
+		if c.code == http.StatusNotModified &&
+			resp.Header.Get("Content-Type") != "" {
+			t.Errorf("%d: unexpected Content-Type on 304", i)
+		}
+		if c.method == http.MethodHead && c.code == http.StatusOK &&
+			resp.Header.Get("Content-Length") != "14" {
+			t.Errorf("%d: expected HEAD Content-Length 14; actual %q", i,
+				resp.Header.Get("Content-Length"))
+		}
+	}
+}
+
+func TestConditionalLargeResponse(t *testing.T) {
+	body := strings.Repeat("x", 65)
+	handler := Conditional(64)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body[:10]))
+			_, _ = w.Write([]byte(body[10:]))
+		},
+	))
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	r.Header.Set("If-None-Match", "*")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	resp := w.Result()
+
+	// 버퍼보다 큰 응답은 ETag 없이 그대로 응답
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected %d; actual %d", http.StatusOK, resp.StatusCode)
+	}
+	if resp.Header.Get("ETag") != "" {
+		t.Error("unexpected ETag on streamed response")
+	}
+	if w.Body.String() != body {
+		t.Errorf("expected %q; actual %q", body, w.Body)
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS 미들웨어의 설정
+type CORSOptions struct {
+	// 허용할 오리진 목록. "*"가 있으면 모든 오리진을 허용
+	// AllowCredentials와 함께 "*"를 사용할 수는 없음
+	AllowedOrigins []string
+
+	// 프리플라이트 요청에 허용할 메서드. 비어 있으면 GET, HEAD, POST를 허용
+	AllowedMethods []string
+
+	// 프리플라이트 요청에 허용할 요청 헤더. 비어 있으면 클라이언트가 요청한 헤더를 모두 허용
+	AllowedHeaders []string
+
+	// 브라우저의 스크립트가 읽을 수 있는 응답 헤더
+	ExposedHeaders []string
+
+	// 쿠키 등 인증 정보를 포함한 요청을 허용할지 여부
+	// 허용하면 Access-Control-Allow-Origin 헤더에 "*" 대신 요청한 오리진을 그대로 응답하므로,
+	// 허용할 오리진을 명시적으로 나열해야 함
+	AllowCredentials bool
+
+	// 브라우저가 프리플라이트 응답을 캐시할 시간. 0이면 헤더를 보내지 않음
+	MaxAge time.Duration
+}
+
+// 교차 출처 리소스 공유(CORS) 헤더를 추가하는 미들웨어
+// 허용된 오리진의 프리플라이트 요청(Access-Control-Request-Method 헤더가 있는 OPTIONS 요청)에는
+// 핸들러를 호출하지 않고 204 No Content로 응답
+// 허용되지 않은 오리진의 요청은 CORS 헤더 없이 핸들러로 넘겨, 브라우저가 응답을 차단하도록 함
+//
+// AllowedOrigins에 "*"가 있으면서 AllowCredentials가 true이면 panic 발생
+// 모든 사이트가 사용자의 쿠키를 포함한 요청으로 응답을 읽을 수 있게 되기 때문
+func CORS(opts CORSOptions) Middleware {
+	origins := make(map[string]struct{}, len(opts.AllowedOrigins))
+	anyOrigin := false
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" {
+			anyOrigin = true
+		}
+		origins[strings.ToLower(o)] = struct{}{}
+	}
+	if anyOrigin && opts.AllowCredentials {
+		panic(`middleware: CORS origin "*" cannot be used with AllowCredentials`)
+	}
+
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	allowedMethods := strings.Join(methods, ", ")
+	allowedHeaders := strings.Join(opts.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(opts.ExposedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				h := w.Header()
+				// 오리진에 따라 응답 헤더가 달라지므로 캐시를 위해 Vary 헤더를 추가
+				h.Add("Vary", "Origin")
+
+				origin := r.Header.Get("Origin")
+				preflight := r.Method == http.MethodOptions &&
+					r.Header.Get("Access-Control-Request-Method") != ""
+				if preflight {
+					h.Add("Vary", "Access-Control-Request-Method")
+					h.Add("Vary", "Access-Control-Request-Headers")
+				}
+
+				_, ok := origins[strings.ToLower(origin)]
+				if origin == "" || !(ok || anyOrigin) {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				if anyOrigin {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Set("Access-Control-Allow-Origin", origin)
+				}
+				if opts.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+
+				if !preflight {
+					if exposedHeaders != "" {
+						h.Set("Access-Control-Expose-Headers", exposedHeaders)
+					}
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				h.Set("Access-Control-Allow-Methods", allowedMethods)
+				if allowedHeaders != "" {
+					h.Set("Access-Control-Allow-Headers", allowedHeaders)
+				} else if req := r.Header.Get("Access-Control-Request-Headers"); req != "" {
+					h.Set("Access-Control-Allow-Headers", req)
+				}
+				if opts.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age",
+						strconv.Itoa(int(opts.MaxAge.Seconds())))
+				}
+				w.WriteHeader(http.StatusNoContent)
+			},
+		)
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPut},
+		ExposedHeaders:   []string{RequestIDHeader},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		},
+	))
+
+	testCases := []struct {
+		method  string
+		origin  string
+		request string // Access-Control-Request-Method
+		code    int
+		allowed string // Access-Control-Allow-Origin
+		methods string // Access-Control-Allow-Methods
+	}{
+		{http.MethodGet, "", "", http.StatusTeapot, "", ""},
+		{http.MethodGet, "https://example.com", "", http.StatusTeapot,
+			"https://example.com", ""},
+		{http.MethodGet, "https://evil.com", "", http.StatusTeapot, "", ""},
+		{http.MethodOptions, "https://example.com", http.MethodPut,
+			http.StatusNoContent, "https://example.com", "GET, PUT"},
+		// 허용되지 않은 오리진의 프리플라이트 요청은 핸들러로 넘김
+		{http.MethodOptions, "https://evil.com", http.MethodPut,
+			http.StatusTeapot, "", ""},
+		// Access-Control-Request-Method 헤더가 없으면 일반 OPTIONS 요청
+		{http.MethodOptions, "https://example.com", "", http.StatusTeapot,
+			"https://example.com", ""},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(c.method, "http://test/", nil)
+		if c.origin != "" {
+			r.Header.Set("Origin", c.origin)
+		}
+		if c.request != "" {
+			r.Header.Set("Access-Control-Request-Method", c.request)
+			r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, resp.StatusCode)
+		}
+		h := resp.Header
+		if actual := h.Get("Access-Control-Allow-Origin"); actual != c.allowed {
+			t.Errorf("%d: expected origin %q; actual %q", i, c.allowed, actual)
+		}
+		if actual := h.Get("Access-Control-Allow-Methods"); actual != c.methods {
+			t.Errorf("%d: expected methods %q; actual %q", i, c.methods, actual)
+		}
+		if h.Get("Vary") != "Origin" {
+			t.Errorf("%d: expected Vary: Origin", i)
+		}
+		if c.allowed == "" {
+			continue
+		}
+		if h.Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("%d: expected credentials allowed", i)
+		}
+		if c.methods != "" {
+			if h.Get("Access-Control-Allow-Headers") != "X-Custom" ||
+				h.Get("Access-Control-Max-Age") != "600" {
+				t.Errorf("%d: unexpected preflight headers %v", i, h)
+			}
+		} else if h.Get("Access-Control-Expose-Headers") != RequestIDHeader {
+			t.Errorf("%d: expected exposed headers", i)
+		}
+	}
+}
+
+func TestCORSAnyOriginWithCredentials(t *testing.T) {
+	// 모든 오리진을 허용하면서 인증 정보를 포함한 요청을 허용할 수는 없음
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for origin \"*\" with credentials")
+		}
+	}()
+	CORS(CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	handler := CORS(CORSOptions{AllowedOrigins: []string{"*"}})(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	h := w.Result().Header
+	if actual := h.Get("Access-Control-Allow-Origin"); actual != "*" {
+		t.Errorf("expected origin %q; actual %q", "*", actual)
+	}
+	if h.Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("unexpected Access-Control-Allow-Credentials header")
+	}
+}
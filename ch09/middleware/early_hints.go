@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// 브라우저가 미리 가져올 리소스
+type Preload struct {
+	Href        string `json:"href"`                  // 리소스의 경로
+	As          string `json:"as"`                    // 리소스의 종류 (style, script, image, font 등)
+	Type        string `json:"type,omitempty"`        // 리소스의 MIME 형식
+	CrossOrigin string `json:"crossorigin,omitempty"` // 폰트 등 CORS로 가져올 리소스는 "anonymous"
+}
+
+// Link 헤더의 값
+func (p Preload) String() string {
+	s := fmt.Sprintf("<%s>; rel=preload; as=%s", p.Href, p.As)
+	if p.Type != "" {
+		s += fmt.Sprintf("; type=%q", p.Type)
+	}
+	if p.CrossOrigin != "" {
+		s += "; crossorigin=" + p.CrossOrigin
+	}
+
+	return s
+}
+
+// 요청 경로별로 미리 가져올 리소스 목록
+//
+//	{
+//	  "/": [
+//	    {"href": "/static/style.css", "as": "style"},
+//	    {"href": "/static/hiking.svg", "as": "image"}
+//	  ]
+//	}
+type PreloadManifest map[string][]Preload
+
+// JSON 형식의 매니페스트 파일을 읽음
+func LoadPreloadManifest(path string) (PreloadManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m PreloadManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for route, preloads := range m {
+		if !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("%s: route %q must begin with /", path,
+				route)
+		}
+		for _, p := range preloads {
+			if p.Href == "" || p.As == "" {
+				return nil, fmt.Errorf("%s: route %q: preload needs href and as",
+					path, route)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// 매니페스트에 있는 경로의 GET 요청에, 핸들러를 호출하기 전에
+// Link: rel=preload 헤더를 담은 103 Early Hints 정보성 응답을 먼저 보내는 미들웨어
+// 서버가 최종 응답을 준비하는 동안 브라우저는 스타일시트나 이미지를 미리 요청할 수 있음
+// 브라우저가 더 이상 지원하지 않는 HTTP/2 서버 푸시와 달리, 브라우저가 캐시를 확인한 후 필요한 리소스만 요청함
+//
+// Link 헤더는 최종 응답에도 그대로 포함되므로, 103 응답을 무시하는 클라이언트도 리소스를 미리 가져올 수 있음
+// HTTP/1.0 클라이언트는 정보성 응답을 이해하지 못하므로 보내지 않음
+func EarlyHints(m PreloadManifest) Middleware {
+	links := make(map[string][]string, len(m))
+	for route, preloads := range m {
+		for _, p := range preloads {
+			links[route] = append(links[route], p.String())
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if l, ok := links[r.URL.Path]; ok && len(l) > 0 &&
+					r.Method == http.MethodGet && r.ProtoAtLeast(1, 1) {
+					for _, v := range l {
+						w.Header().Add("Link", v)
+					}
+					w.WriteHeader(http.StatusEarlyHints)
+				}
+
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestEarlyHints(t *testing.T) {
+	manifest := PreloadManifest{
+		"/": {
+			{Href: "/static/style.css", As: "style"},
+			{Href: "/static/font.woff2", As: "font", Type: "font/woff2",
+				CrossOrigin: "anonymous"},
+		},
+	}
+	links := []string{
+		"</static/style.css>; rel=preload; as=style",
+		`</static/font.woff2>; rel=preload; as=font; type="font/woff2"; crossorigin=anonymous`,
+	}
+
+	// 클라이언트가 103 응답을 받을 때까지 핸들러는 최종 응답을 보내지 않음
+	received := make(chan struct{}, 1)
+	core, logs := observer.New(zap.InfoLevel)
+	srv := httptest.NewServer(Chain(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Expect-Hints") != "" {
+				select {
+				case <-received:
+				case <-time.After(5 * time.Second):
+					t.Error("timed out waiting for early hints")
+				}
+			}
+			_, _ = w.Write([]byte("Hello, friend!"))
+		}),
+		AccessLog(zap.New(core)),
+		EarlyHints(manifest),
+		Gzip(gzip.DefaultCompression),
+		Conditional(1<<20),
+	))
+	defer srv.Close()
+
+	testCases := []struct {
+		method string
+		path   string
+		hints  bool
+	}{
+		{http.MethodGet, "/", true},
+		{http.MethodHead, "/", false},
+		{http.MethodGet, "/other", false},
+	}
+
+	for i, c := range testCases {
+		var (
+			mu     sync.Mutex
+			events []string
+			hints  []textproto.MIMEHeader
+		)
+		trace := &httptrace.ClientTrace{
+			Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if code == http.StatusEarlyHints {
+					events = append(events, "103")
+					hints = append(hints, header)
+					received <- struct{}{}
+				}
+				return nil
+			},
+		}
+
+		req, err := http.NewRequestWithContext(
+			httptrace.WithClientTrace(context.Background(), trace),
+			c.method, srv.URL+c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.hints {
+			req.Header.Set("X-Expect-Hints", "1")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		events = append(events, "final")
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%d: expected %d; actual %d", i, http.StatusOK,
+				resp.StatusCode)
+		}
+
+		if !c.hints {
+			if len(hints) != 0 {
+				t.Errorf("%d: unexpected early hints %v", i, hints)
+			}
+			continue
+		}
+
+		// 정보성 응답이 최종 응답보다 먼저 도착해야 함
+		if len(events) != 2 || events[0] != "103" {
+			t.Errorf("%d: expected 103 before the final response; actual %v",
+				i, events)
+		}
+		if len(hints) != 1 {
+			t.Fatalf("%d: expected 1 early hints response; actual %d", i,
+				len(hints))
+		}
+		for _, h := range []http.Header{http.Header(hints[0]), resp.Header} {
+			actual := h.Values("Link")
+			if len(actual) != len(links) {
+				t.Errorf("%d: expected links %q; actual %q", i, links, actual)
+				continue
+			}
+			for j := range links {
+				if actual[j] != links[j] {
+					t.Errorf("%d: expected link %q; actual %q", i, links[j],
+						actual[j])
+				}
+			}
+		}
+	}
+
+	// 접근 로그에는 정보성 응답이 아닌 최종 응답의 상태 코드를 기록
+	for _, e := range logs.All() {
+		if status := e.ContextMap()["status"]; status != int64(http.StatusOK) {
+			t.Errorf("expected logged status %d; actual %v", http.StatusOK,
+				status)
+		}
+	}
+}
+
+func TestLoadPreloadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		json string
+		ok   bool
+	}{
+		{`{"/": [{"href": "/static/style.css", "as": "style"}]}`, true},
+		{`{"/": [{"href": "/static/style.css"}]}`, false},
+		{`{"index": [{"href": "/static/style.css", "as": "style"}]}`, false},
+		{`[]`, false},
+	}
+
+	for i, c := range testCases {
+		path := filepath.Join(dir, "manifest.json")
+		if err := os.WriteFile(path, []byte(c.json), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := LoadPreloadManifest(path)
+		if (err == nil) != c.ok {
+			t.Errorf("%d: unexpected error %v", i, err)
+			continue
+		}
+		if c.ok && m["/"][0].String() != "</static/style.css>; rel=preload; as=style" {
+			t.Errorf("%d: unexpected manifest %v", i, m)
+		}
+	}
+}
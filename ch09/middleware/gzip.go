@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 이보다 작은 응답은 압축 효과보다 오버헤드가 더 크므로 압축하지 않음
+const gzipMinSize = 256
+
+// 응답 body를 gzip으로 압축하는 미들웨어. level은 gzip.DefaultCompression 등의 압축 수준
+// 클라이언트가 Accept-Encoding 헤더로 gzip을 허용한 경우에만 압축하며,
+// 이미 인코딩됐거나 압축된 형식(이미지, 동영상, 아카이브 등)의 응답, 부분 응답, body가 없는 응답은 그대로 전달
+// 압축 여부에 따라 응답이 달라지므로 캐시를 위해 Vary: Accept-Encoding 헤더를 추가
+func Gzip(level int) Middleware {
+	pool := &sync.Pool{
+		New: func() any {
+			w, err := gzip.NewWriterLevel(nil, level)
+			if err != nil {
+				// 잘못된 압축 수준은 프로그래밍 에러
+				panic(err)
+			}
+			return w
+		},
+	}
+
+	// 잘못된 압축 수준을 요청을 받기 전에 알 수 있도록 미리 생성
+	pool.Put(pool.Get())
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				gw := &gzipResponseWriter{
+					ResponseWriter: w,
+					pool:           pool,
+					accepts:        r.Method != http.MethodHead && acceptsGzip(r),
+				}
+				defer gw.close()
+
+				next.ServeHTTP(gw, r)
+			},
+		)
+	}
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	pool    *sync.Pool
+	accepts bool // 클라이언트가 gzip을 허용하는지 여부
+
+	status  int  // 핸들러가 WriteHeader 메서드로 지정한 상태 코드
+	decided bool // 압축 여부를 결정하고 응답 헤더를 보냈는지 여부
+	gz      *gzip.Writer
+}
+
+// 압축 여부는 Content-Type 헤더나 첫 번째 body에 따라 달라지므로, 상태 코드만 기록하고 응답 헤더는 나중에 보냄
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	// 103 Early Hints 같은 정보성 응답은 최종 응답이 아니므로 그대로 전달
+	if status >= 100 && status < 200 {
+		g.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if g.status == 0 {
+		g.status = status
+	}
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	g.decide(p)
+	if g.gz != nil {
+		return g.gz.Write(p)
+	}
+
+	return g.ResponseWriter.Write(p)
+}
+
+func (g *gzipResponseWriter) Flush() {
+	g.decide(nil)
+	if g.gz != nil {
+		_ = g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter { return g.ResponseWriter }
+
+// 압축 여부를 결정하고 응답 헤더를 보냄
+// p는 첫 번째로 쓰는 body로, Content-Type 헤더가 없을 때 형식을 추측하는 데 사용
+func (g *gzipResponseWriter) decide(p []byte) {
+	if g.decided {
+		return
+	}
+	g.decided = true
+
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	defer g.ResponseWriter.WriteHeader(g.status)
+
+	h := g.Header()
+	// 압축된 바이트로 형식을 추측하지 않도록, 압축 전 body로 미리 추측해 둠
+	if _, ok := h["Content-Type"]; !ok && len(p) > 0 {
+		h.Set("Content-Type", http.DetectContentType(p))
+	}
+
+	if !compressible(g.status, h) {
+		return
+	}
+	addVary(h, "Accept-Encoding")
+
+	if !g.accepts {
+		return
+	}
+	if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil &&
+		n < gzipMinSize {
+		return
+	}
+
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	// 압축된 응답은 원본과 바이트 단위로 같지 않으므로 강한 ETag를 약한 ETag로 바꿈
+	if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		h.Set("ETag", "W/"+etag)
+	}
+
+	g.gz = g.pool.Get().(*gzip.Writer)
+	g.gz.Reset(g.ResponseWriter)
+}
+
+func (g *gzipResponseWriter) close() {
+	// body 없이 WriteHeader 메서드만 호출한 핸들러의 응답 헤더를 보냄
+	if !g.decided && g.status != 0 {
+		g.decide(nil)
+	}
+	if g.gz == nil {
+		return
+	}
+
+	_ = g.gz.Close()
+	g.gz.Reset(nil)
+	g.pool.Put(g.gz)
+	g.gz = nil
+}
+
+func compressible(status int, h http.Header) bool {
+	switch {
+	case status == http.StatusNoContent, status == http.StatusNotModified,
+		status == http.StatusPartialContent:
+		return false
+	case h.Get("Content-Encoding") != "", h.Get("Content-Range") != "":
+		return false
+	}
+
+	ct := strings.ToLower(h.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(ct, "image/svg+xml"):
+		return true
+	case strings.HasPrefix(ct, "image/"), strings.HasPrefix(ct, "video/"),
+		strings.HasPrefix(ct, "audio/"), strings.HasPrefix(ct, "font/woff"),
+		strings.HasPrefix(ct, "application/zip"),
+		strings.HasPrefix(ct, "application/gzip"),
+		strings.HasPrefix(ct, "application/x-gzip"),
+		strings.HasPrefix(ct, "application/octet-stream"):
+		return false
+	}
+
+	return true
+}
+
+// Accept-Encoding 헤더에 q=0이 아닌 gzip 또는 *가 있는지 확인
+func acceptsGzip(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept-Encoding") {
+		for _, enc := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "gzip" && name != "*" {
+				continue
+			}
+
+			q := 1.0
+			for _, p := range strings.Split(params, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+				if ok && strings.EqualFold(k, "q") {
+					q, _ = strconv.ParseFloat(v, 64)
+				}
+			}
+			if q > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Vary 헤더에 값이 없을 때만 추가
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(f), value) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", value)
+}
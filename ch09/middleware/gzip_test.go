@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzip(t *testing.T) {
+	text := strings.Repeat("Hello, friend! ", 100)
+	handler := Gzip(gzip.DefaultCompression)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/image":
+				w.Header().Set("Content-Type", "image/png")
+			case "/small":
+				w.Header().Set("Content-Length", "5")
+				_, _ = w.Write([]byte("small"))
+				return
+			case "/etag":
+				w.Header().Set("ETag", `"v1"`)
+			case "/vary":
+				w.Header().Set("Vary", "Origin, Accept-Encoding")
+			case "/empty":
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = w.Write([]byte(text))
+		},
+	))
+
+	testCases := []struct {
+		path           string
+		acceptEncoding string
+		gzipped        bool
+		vary           bool
+	}{
+		{"/", "gzip, deflate", true, true},
+		{"/", "deflate", false, true},
+		{"/", "gzip;q=0", false, true},
+		{"/", "*", true, true},
+		{"/", "", false, true},
+		{"/image", "gzip", false, false},
+		{"/small", "gzip", false, true},
+		{"/etag", "gzip", true, true},
+		{"/empty", "gzip", false, false},
+		{"/vary", "gzip", true, true},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://test"+c.path, nil)
+		if c.acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", c.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		gzipped := resp.Header.Get("Content-Encoding") == "gzip"
+		if gzipped != c.gzipped {
+			t.Errorf("%d: expected gzipped %t; actual %t", i, c.gzipped, gzipped)
+			continue
+		}
+		if vary := strings.HasSuffix(resp.Header.Get("Vary"), "Accept-Encoding"); vary != c.vary {
+			t.Errorf("%d: expected vary %t; actual %t", i, c.vary, vary)
+		}
+		if !gzipped {
+			continue
+		}
+
+		if n := len(resp.Header.Values("Vary")); n != 1 {
+			t.Errorf("%d: expected 1 Vary header; actual %d", i, n)
+		}
+		if resp.Header.Get("Content-Length") != "" {
+			t.Errorf("%d: unexpected Content-Length", i)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("%d: expected sniffed text/plain; actual %q", i, ct)
+		}
+		if c.path == "/etag" && resp.Header.Get("ETag") != `W/"v1"` {
+			t.Errorf("%d: expected weak ETag; actual %q", i,
+				resp.Header.Get("ETag"))
+		}
+
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != text {
+			t.Errorf("%d: unexpected body %q", i, b)
+		}
+	}
+}
+
+func TestGzipEarlyHints(t *testing.T) {
+	handler := Gzip(gzip.BestSpeed)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Link", "</style.css>; rel=preload; as=style")
+			w.WriteHeader(http.StatusEarlyHints)
+			_, _ = w.Write([]byte(strings.Repeat("x", gzipMinSize)))
+		},
+	))
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 기본 Transport가 자동으로 압축을 해제하지 않도록 직접 Accept-Encoding 헤더를 지정
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected %d; actual %d", http.StatusOK, resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Error("expected gzipped final response")
+	}
+}
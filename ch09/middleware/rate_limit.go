@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"container/list"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 기본적으로 추적할 최대 클라이언트 수
+	DefaultMaxClients = 10000
+
+	// IPv6 클라이언트를 하나의 버킷으로 묶는 기본 접두사 길이
+	// 보통 가입자 하나에게 /64 대역 전체를 할당하므로, 주소마다 버킷을 만들면
+	// 클라이언트가 주소를 바꿔 가며 제한을 우회하고 LRU의 다른 클라이언트를 밀어낼 수 있음
+	DefaultIPv6Prefix = 64
+)
+
+// 클라이언트별 속도 제한 미들웨어의 설정
+type RateLimitOptions struct {
+	// 클라이언트마다 초당 채워지는 토큰의 수. 요청 하나에 토큰 하나를 소비
+	Rate float64
+
+	// 버킷의 크기. 클라이언트는 최대 Burst개의 요청을 연달아 보낼 수 있음
+	Burst int
+
+	// X-Forwarded-For 헤더를 신뢰할 프록시의 주소 대역
+	// 비어 있으면 헤더를 무시하고 연결의 원격 주소만 사용함
+	TrustedProxies []netip.Prefix
+
+	// 메모리 사용량을 제한하기 위해 추적할 최대 클라이언트 수
+	// 이를 넘으면 가장 오래 요청이 없던 클라이언트의 버킷부터 제거. 0이면 DefaultMaxClients
+	MaxClients int
+
+	// IPv6 클라이언트를 이 길이의 접두사 단위로 묶어 같은 버킷을 사용하게 함
+	// 0이면 DefaultIPv6Prefix. 주소마다 따로 제한하려면 128을 지정
+	IPv6Prefix int
+
+	// 현재 시각을 반환하는 함수. nil이면 time.Now를 사용
+	Now func() time.Time
+}
+
+// 클라이언트 IP 주소별로 토큰 버킷 알고리즘을 적용해 요청 속도를 제한하는 미들웨어
+// 버킷의 토큰이 바닥난 클라이언트에게는 핸들러를 호출하지 않고
+// 다음 토큰이 채워질 때까지의 시간을 Retry-After 헤더에 담아 429 Too Many Requests를 응답
+func RateLimit(opts RateLimitOptions) Middleware {
+	l := newRateLimiter(opts)
+	bits := opts.IPv6Prefix
+	if bits <= 0 || bits > 128 {
+		bits = DefaultIPv6Prefix
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				ok, wait := l.allow(
+					rateLimitKey(ClientIP(r, opts.TrustedProxies), bits))
+				if !ok {
+					secs := int(math.Ceil(wait.Seconds()))
+					w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+					http.Error(w, "Too many requests",
+						http.StatusTooManyRequests)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
+
+// 요청을 보낸 클라이언트의 IP 주소를 반환
+// IPv4에 대응된 IPv6 주소(::ffff:a.b.c.d)는 IPv4 주소(a.b.c.d)로 변환함
+// 연결의 원격 주소가 신뢰하는 프록시라면 X-Forwarded-For 헤더를 오른쪽(가장 최근에 추가된 주소)부터 거슬러 올라가며,
+// 신뢰하지 않는 첫 번째 주소를 클라이언트로 간주함
+// 클라이언트가 직접 보낸 X-Forwarded-For 헤더는 얼마든지 위조할 수 있으므로, 맨 왼쪽 주소를 그대로 믿으면 안 됨
+func ClientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	ip = ip.Unmap()
+	if !trustedProxy(ip, trusted) {
+		return ip.String()
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			// 해석할 수 없는 주소 이전의 값은 신뢰할 수 없으므로 마지막으로 확인한 주소를 사용
+			break
+		}
+		ip = addr.Unmap()
+		if !trustedProxy(ip, trusted) {
+			break
+		}
+	}
+
+	return ip.String()
+}
+
+// 클라이언트 IP 주소로 속도 제한 버킷의 키를 생성
+// IPv6 주소는 bits 길이의 접두사로 묶고, IPv4 주소나 해석할 수 없는 값은 그대로 사용
+func rateLimitKey(ip string, bits int) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || !addr.Is6() {
+		return ip
+	}
+
+	// 접두사에 포함되지 않는 영역(zone)은 제거
+	p, err := addr.WithZone("").Prefix(bits)
+	if err != nil {
+		return ip
+	}
+
+	return p.String()
+}
+
+func trustedProxy(ip netip.Addr, trusted []netip.Prefix) bool {
+	ip = ip.Unmap()
+	for _, p := range trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time // 마지막으로 토큰을 채운 시각
+}
+
+// 클라이언트별 토큰 버킷을 LRU 리스트로 관리
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	max   int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	lru     *list.List // 앞쪽일수록 최근에 요청한 클라이언트
+}
+
+func newRateLimiter(opts RateLimitOptions) *rateLimiter {
+	l := &rateLimiter{
+		rate:    opts.Rate,
+		burst:   float64(max(opts.Burst, 1)),
+		max:     opts.MaxClients,
+		now:     opts.Now,
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+	if l.max <= 0 {
+		l.max = DefaultMaxClients
+	}
+	if l.now == nil {
+		l.now = time.Now
+	}
+
+	return l
+}
+
+// key의 버킷에서 토큰 하나를 소비
+// 토큰이 없으면 false와 함께 다음 토큰이 채워질 때까지 기다려야 하는 시간을 반환
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var b *bucket
+	if e, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(e)
+		b = e.Value.(*bucket)
+		elapsed := now.Sub(b.last).Seconds()
+		if elapsed > 0 {
+			b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+		}
+		b.last = now
+	} else {
+		// 처음 보는 클라이언트는 가득 찬 버킷으로 시작
+		// 가장 오래된 버킷을 제거해도, 그 클라이언트는 다음 요청 때 가득 찬 버킷을 받을 뿐이므로 제한이 느슨해질 뿐임
+		if l.lru.Len() >= l.max {
+			oldest := l.lru.Back()
+			l.lru.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+		b = &bucket{key: key, tokens: l.burst, last: now}
+		l.buckets[key] = l.lru.PushFront(b)
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if l.rate <= 0 {
+		return false, time.Hour
+	}
+
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *rateLimiter) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lru.Len()
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler := RateLimit(RateLimitOptions{
+		Rate:  0.5, // 2초에 토큰 하나
+		Burst: 2,
+		Now:   func() time.Time { return now },
+	})(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+
+	testCases := []struct {
+		remote     string
+		advance    time.Duration // 요청 전에 흐른 시간
+		code       int
+		retryAfter string
+	}{
+		{"10.0.0.1:1000", 0, http.StatusOK, ""},
+		{"10.0.0.1:1001", 0, http.StatusOK, ""},
+		{"10.0.0.1:1002", 0, http.StatusTooManyRequests, "2"},
+		// 다른 클라이언트는 별도의 버킷을 사용
+		{"10.0.0.2:1000", 0, http.StatusOK, ""},
+		{"10.0.0.1:1003", time.Second, http.StatusTooManyRequests, "1"},
+		{"10.0.0.1:1004", time.Second, http.StatusOK, ""},
+		{"10.0.0.1:1005", 0, http.StatusTooManyRequests, "2"},
+	}
+
+	for i, c := range testCases {
+		now = now.Add(c.advance)
+		r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+		r.RemoteAddr = c.remote
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		resp := w.Result()
+
+		if resp.StatusCode != c.code {
+			t.Errorf("%d: expected %d; actual %d", i, c.code, resp.StatusCode)
+		}
+		if actual := resp.Header.Get("Retry-After"); actual != c.retryAfter {
+			t.Errorf("%d: expected Retry-After %q; actual %q", i,
+				c.retryAfter, actual)
+		}
+	}
+}
+
+func TestRateLimitEviction(t *testing.T) {
+	l := newRateLimiter(RateLimitOptions{Rate: 1, Burst: 1, MaxClients: 3})
+
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.allow(fmt.Sprint(i)); !ok {
+			t.Fatalf("%d: expected a new client to be allowed", i)
+		}
+	}
+	if n := l.len(); n != 3 {
+		t.Fatalf("expected 3 buckets; actual %d", n)
+	}
+
+	// 가장 최근의 클라이언트는 남아 있으므로 토큰이 없음
+	if ok, _ := l.allow("9"); ok {
+		t.Error("expected recent client to be limited")
+	}
+	// 제거된 클라이언트는 가득 찬 버킷으로 다시 시작
+	if ok, _ := l.allow("0"); !ok {
+		t.Error("expected evicted client to be allowed")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::1/128"),
+	}
+
+	testCases := []struct {
+		remote string
+		xff    string
+		ip     string
+	}{
+		{"192.0.2.1:1234", "", "192.0.2.1"},
+		// 신뢰하지 않는 원격 주소가 보낸 헤더는 무시
+		{"192.0.2.1:1234", "198.51.100.1", "192.0.2.1"},
+		{"10.0.0.1:1234", "", "10.0.0.1"},
+		{"10.0.0.1:1234", "198.51.100.1", "198.51.100.1"},
+		// 클라이언트가 위조한 맨 왼쪽 주소가 아닌, 신뢰하는 프록시가 추가한 주소를 사용
+		{"10.0.0.1:1234", "1.2.3.4, 198.51.100.1, 10.0.0.2", "198.51.100.1"},
+		{"[::1]:1234", "2001:db8::1", "2001:db8::1"},
+		{"10.0.0.1:1234", "1.2.3.4, garbage", "10.0.0.1"},
+		{"10.0.0.1:1234", "10.0.0.3, 10.0.0.2", "10.0.0.3"},
+		// IPv4에 대응된 IPv6 주소는 IPv4 주소와 같은 클라이언트
+		{"[::ffff:192.0.2.1]:1234", "", "192.0.2.1"},
+		{"[::ffff:10.0.0.1]:1234", "198.51.100.1", "198.51.100.1"},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+		r.RemoteAddr = c.remote
+		if c.xff != "" {
+			r.Header.Set("X-Forwarded-For", c.xff)
+		}
+
+		if actual := ClientIP(r, trusted); actual != c.ip {
+			t.Errorf("%d: expected %q; actual %q", i, c.ip, actual)
+		}
+	}
+}
+
+func TestRateLimitIPv6Prefix(t *testing.T) {
+	testCases := []struct {
+		bits    int
+		remotes []string
+		allowed []bool
+	}{
+		// 같은 /64 대역의 주소는 하나의 버킷을 공유
+		{0, []string{"[2001:db8::1]:1", "[2001:db8::2]:1", "[2001:db8:0:1::1]:1"},
+			[]bool{true, false, true}},
+		{128, []string{"[2001:db8::1]:1", "[2001:db8::2]:1", "[2001:db8::1]:2"},
+			[]bool{true, true, false}},
+		// IPv4 주소와, 그 주소에 대응된 IPv6 주소는 하나의 버킷을 공유
+		{0, []string{"192.0.2.1:1", "[::ffff:192.0.2.1]:1"},
+			[]bool{true, false}},
+	}
+
+	for i, c := range testCases {
+		handler := RateLimit(RateLimitOptions{Rate: 0, Burst: 1, IPv6Prefix: c.bits})(
+			http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		for j, remote := range c.remotes {
+			r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+			r.RemoteAddr = remote
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if allowed := w.Code == http.StatusOK; allowed != c.allowed[j] {
+				t.Errorf("%d: %s: expected allowed %t; actual status %d", i,
+					remote, c.allowed[j], w.Code)
+			}
+		}
+	}
+}
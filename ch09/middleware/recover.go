@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// 핸들러의 패닉을 복구해 스택 트레이스를 기록하고 500 Internal Server Error를 응답하는 미들웨어
+// 응답 헤더를 이미 보낸 후라면 상태 코드를 바꿀 수 없으므로 연결을 끊어 클라이언트가 불완전한 응답임을 알 수 있게 함
+// http.ErrAbortHandler 패닉은 응답을 중단하라는 의도이므로 기록하지 않음
+func Recover(log *zap.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				rec := &responseRecorder{ResponseWriter: w}
+
+				defer func() {
+					v := recover()
+					if v == nil {
+						return
+					}
+					if v == http.ErrAbortHandler {
+						panic(v)
+					}
+
+					log.Error("panic serving request",
+						zap.Any("panic", v),
+						zap.String("method", r.Method),
+						zap.String("path", r.URL.Path),
+						zap.String("request_id", RequestIDFromContext(r.Context())),
+						zap.ByteString("stack", debug.Stack()),
+					)
+
+					if rec.wroteHeader() {
+						panic(http.ErrAbortHandler)
+					}
+					http.Error(w, "Internal server error",
+						http.StatusInternalServerError)
+				}()
+
+				next.ServeHTTP(rec, r)
+			},
+		)
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecover(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	handler := Recover(zap.New(core))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		},
+	))
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if actual := w.Result().StatusCode; actual != http.StatusInternalServerError {
+		t.Errorf("expected %d; actual %d", http.StatusInternalServerError,
+			actual)
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("expected 1 log entry; actual %d", logs.Len())
+	}
+	if actual := logs.All()[0].ContextMap()["panic"]; actual != "boom" {
+		t.Errorf("expected %q; actual %v", "boom", actual)
+	}
+}
+
+func TestRecoverAfterHeader(t *testing.T) {
+	handler := Recover(zap.NewNop())(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("partial"))
+			panic("boom")
+		},
+	))
+
+	// 응답 헤더를 이미 보냈다면 http.ErrAbortHandler 패닉으로 연결을 끊음
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("expected %v; actual %v", http.ErrAbortHandler, v)
+		}
+	}()
+
+	r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// 요청 ID를 주고받는 헤더
+const RequestIDHeader = "X-Request-ID"
+
+// 클라이언트가 보낸 요청 ID로 허용하는 최대 길이
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// 요청마다 ID를 부여해 요청의 context와 응답 헤더에 추가하는 미들웨어
+// 프록시 등이 X-Request-ID 헤더로 ID를 이미 부여했다면 그 ID를 그대로 사용해, 여러 서버의 로그를 하나의 요청으로 묶을 수 있음
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+				r.Header.Set(RequestIDHeader, id)
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(
+				context.WithValue(r.Context(), requestIDKey{}, id)))
+		},
+	)
+}
+
+// RequestID 미들웨어가 부여한 요청 ID를 반환. 없으면 빈 문자열을 반환
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// 로그를 오염시키지 않도록, 출력 가능한 ASCII 문자로만 이뤄진 적당한 길이의 ID만 허용
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
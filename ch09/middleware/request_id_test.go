@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var fromContext string
+	handler := RequestID(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fromContext = RequestIDFromContext(r.Context())
+		},
+	))
+
+	testCases := []struct {
+		header    string
+		preserved bool
+	}{
+		{"", false},
+		{"abc-123", true},
+		{"bad id", false},
+		{"bad\nid", false},
+		{strings.Repeat("a", maxRequestIDLen+1), false},
+	}
+
+	for i, c := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://test/", nil)
+		if c.header != "" {
+			r.Header.Set(RequestIDHeader, c.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		actual := w.Result().Header.Get(RequestIDHeader)
+		if actual == "" {
+			t.Errorf("%d: expected a request ID", i)
+			continue
+		}
+		if actual != fromContext {
+			t.Errorf("%d: expected context ID %q; actual %q", i, actual,
+				fromContext)
+		}
+		if c.preserved != (actual == c.header) {
+			t.Errorf("%d: unexpected request ID %q for %q", i, actual,
+				c.header)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// 핸들러가 응답한 상태 코드와 body의 크기를 기록하는 http.ResponseWriter
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	// 103 Early Hints 같은 정보성 응답은 최종 응답이 아니므로 기록하지 않음
+	if r.status == 0 && (status < 100 || status >= 200) {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// 핸들러가 아무것도 쓰지 않았다면 net/http가 200 OK를 응답함
+func (r *responseRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
+
+func (r *responseRecorder) wroteHeader() bool { return r.status != 0 }
+
+func (r *responseRecorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, errors.New("hijacking not supported")
+}
+
+// http.ResponseController가 원래의 http.ResponseWriter를 찾을 수 있도록 함
+func (r *responseRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
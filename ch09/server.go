@@ -1,14 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
+	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"strings"
 	"time"
 
+	"go.uber.org/zap"
+
+	"github.com/awoodbeck/gnp/ch09/auth"
 	"github.com/awoodbeck/gnp/ch09/handlers"
 	"github.com/awoodbeck/gnp/ch09/middleware"
 )
@@ -17,16 +27,84 @@ import (
 // 이를 위해 매개변수로 인증서의 경로와 인증서의 개인키 경로를 전달해 줘야 함
 // 둘 중 하나의 값이 전달되지 않으면, 서버는 평문의 HTTP 연결을 대기함
 var (
-	addr  = flag.String("listen", "127.0.0.1:8080", "listen address")
-	cert  = flag.String("cert", "", "certificate")
-	pkey  = flag.String("key", "", "private key")
-	files = flag.String("files", "./files", "static file directory")
+	addr       = flag.String("listen", "127.0.0.1:8080", "listen address")
+	cert       = flag.String("cert", "", "certificate")
+	pkey       = flag.String("key", "", "private key")
+	files      = flag.String("files", "./files", "static file directory")
+	embedFiles = flag.Bool("embed", false,
+		"serve the static files embedded in the binary instead of -files")
+	preload = flag.String("preload", "./preload.json",
+		"route to preload manifest for 103 Early Hints; empty disables hints")
+	body = flag.Int64("max-body", 1<<20, "maximum request body size in bytes")
+
+	// 클라이언트 IP 주소별 요청 속도 제한. rate가 0이면 제한하지 않음
+	rate    = flag.Float64("rate", 10, "requests per second per client")
+	burst   = flag.Int("burst", 20, "maximum burst of requests per client")
+	proxies = flag.String("trusted-proxies", "",
+		"comma-separated proxy addresses or CIDRs trusted for X-Forwarded-For")
+
+	// 인증. 하나라도 지정하면 모든 요청에 인증을 요구함
+	htpasswd = flag.String("htpasswd", "", "bcrypt credentials file for Basic auth")
+	jwtKey   = flag.String("jwt-key", "", "file containing the HMAC key for bearer tokens")
+	clientCA = flag.String("client-ca", "",
+		"CA certificates for client certificate auth (requires -cert and -key)")
 )
 
+// run 함수에 전달하는 서버의 부가 기능 설정
+type options struct {
+	maxBody   int64
+	limit     middleware.RateLimitOptions
+	authn     *auth.Authenticator // nil이면 인증하지 않음
+	clientCAs *x509.CertPool      // nil이 아니면 TLS 핸드셰이크에서 클라이언트 인증서를 요청
+	preload   middleware.PreloadManifest
+}
+
 func main() {
 	flag.Parse()
+
+	trusted, err := parsePrefixes(*proxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := options{
+		maxBody: *body,
+		limit: middleware.RateLimitOptions{
+			Rate:           *rate,
+			Burst:          *burst,
+			TrustedProxies: trusted,
+		},
+	}
+
+	opts.authn, opts.clientCAs, err = newAuthenticator(*htpasswd, *jwtKey,
+		*clientCA)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.clientCAs != nil && (*cert == "" || *pkey == "") {
+		log.Fatal("-client-ca requires -cert and -key")
+	}
+
+	if *preload != "" {
+		opts.preload, err = middleware.LoadPreloadManifest(*preload)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// 정적 파일은 디스크의 디렉터리나 바이너리에 임베드한 파일 중에서 제공
+	var static fs.FS = os.DirFS(*files)
+	source := *files
+	if *embedFiles {
+		static, err = fs.Sub(embedded, "files")
+		if err != nil {
+			log.Fatal(err)
+		}
+		source = "embedded files"
+	}
+	log.Printf("Serving %s\n", source)
+
 	// run 함수에 CLI의 플래그 값을 전달
-	err := run(*addr, *files, *cert, *pkey)
+	err = run(*addr, static, *cert, *pkey, opts)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -34,59 +112,145 @@ func main() {
 	log.Println("Server stopped")
 }
 
-func run(addr, files, cert, pkey string) error {
-	mux := http.NewServeMux()
+// 쉼표로 구분된 IP 주소나 CIDR 목록을 파싱. IP 주소는 해당 주소 하나만을 포함하는 대역으로 취급
+func parsePrefixes(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if strings.Contains(v, "/") {
+			p, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+
+		ip, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// 인증 플래그로 인증 미들웨어의 설정을 생성. 아무 플래그도 지정하지 않으면 nil을 반환
+func newAuthenticator(htpasswd, jwtKey, clientCA string) (*auth.Authenticator,
+	*x509.CertPool, error) {
+	if htpasswd == "" && jwtKey == "" && clientCA == "" {
+		return nil, nil, nil
+	}
+
+	a := new(auth.Authenticator)
+	if htpasswd != "" {
+		creds, err := auth.LoadCredentials(htpasswd)
+		if err != nil {
+			return nil, nil, err
+		}
+		a.Credentials = creds
+	}
+
+	if jwtKey != "" {
+		key, err := os.ReadFile(jwtKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		key = bytes.TrimSpace(key)
+		if len(key) < 32 {
+			return nil, nil, fmt.Errorf("%s: HMAC key must be at least 32 bytes",
+				jwtKey)
+		}
+		a.Tokens = &auth.TokenValidator{Key: key}
+	}
+
+	var pool *x509.CertPool
+	if clientCA != "" {
+		b, err := os.ReadFile(clientCA)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, nil, fmt.Errorf("%s: no certificates found", clientCA)
+		}
+		a.ClientCerts = true
+	}
+
+	return a, pool, nil
+}
+
+func run(addr string, files fs.FS, cert, pkey string, opts options) error {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logger.Sync() }()
+
+	// 디렉터리 목록과 숨김 파일을 제공하지 않으며, 미리 압축된 파일과 캐시 헤더를 지원하는 정적 파일 핸들러
+	static := handlers.NewStatic(files)
+
+	router := handlers.NewRouter()
 	// 1. 정적 파일을 제공하기 위한 라우트
-	mux.Handle("/static/",
-		http.StripPrefix("/static/",
-			middleware.RestrictPrefix(
-				".", http.FileServer(http.Dir("./files")),
-			),
-		),
-	)
+	router.Handle(http.MethodGet, "/static/*file",
+		http.StripPrefix("/static/", static))
 	// 2. 기본 라우트
-	mux.Handle("/",
-		handlers.Methods{
-			http.MethodGet: http.HandlerFunc(
-				func(w http.ResponseWriter, r *http.Request) {
-					// http.ResponseWriter 인터페이스가 http.Pusher 객체인 경우, 별도의 요청 없이도 클라이언트에게 리소스를 푸시할 수 있음
-					if pusher, ok := w.(http.Pusher); ok {
-						// 서버 푸시를 할 때, 요청이 클라이언트에서 온 것으로 취급하므로 클라이언트 관점에서 리소스의 경로를 지정해 줌
-						targets := []string{
-							"/static/style.css",
-							"/static/hiking.svg",
-						}
-						for _, target := range targets {
-							if err := pusher.Push(target, nil); err != nil {
-								log.Printf("%s push failed: %v", target, err)
-							}
-						}
-					}
-					// 리소스를 푸시해 준 뒤, 핸들러에서 응답을 처리
-					// index.html 파일을 푸시해야 할 리소스보다 먼저 보낸 경우, 클라이언트의 브라우저에서는 푸시를 처리하기 전에 해당 리소스에 대한 요청을 보낼 수도 있음
-					http.ServeFile(w, r, filepath.Join(files, "index.html"))
-				},
-			),
+	// 페이지가 참조하는 리소스는 EarlyHints 미들웨어가 매니페스트에 따라 103 Early Hints 응답으로 미리 알려줌
+	router.HandleFunc(http.MethodGet, "/",
+		func(w http.ResponseWriter, r *http.Request) {
+			static.ServeFile(w, r, "index.html")
 		},
 	)
 	// 3. 절대 경로 /2 를 위한 라우트
-	// 이 파일이 기본 라우트에서 참조하는 동일한 리소스를 참조할 경우, 클라이언트의 웹 브라우저는 /2를 렌더링하는 동안 먼저 기본 라우트로 가서 푸시된 리소스를 사용하도록 함
-	mux.Handle("/2",
-		handlers.Methods{
-			http.MethodGet: http.HandlerFunc(
-				func(w http.ResponseWriter, r *http.Request) {
-					http.ServeFile(w, r, filepath.Join(files, "index2.html"))
-				},
-			),
+	router.HandleFunc(http.MethodGet, "/2",
+		func(w http.ResponseWriter, r *http.Request) {
+			static.ServeFile(w, r, "index2.html")
 		},
 	)
 
+	// 모든 요청은 요청 ID 부여 -> 접근 로그 -> 패닉 복구 -> (속도 제한) -> (인증) -> (103 Early Hints) -> 압축 -> 조건부 요청 -> body 크기 제한을 거쳐 라우터로 전달됨
+	// 속도 제한에 걸린 요청도 접근 로그에 남으며, 인증보다 먼저 속도를 제한해 패스워드 대입 공격을 늦춤
+	chain := []middleware.Middleware{
+		middleware.RequestID,
+		middleware.AccessLog(logger),
+		middleware.Recover(logger),
+	}
+	if opts.limit.Rate > 0 {
+		chain = append(chain, middleware.RateLimit(opts.limit))
+	}
+	if opts.authn != nil {
+		chain = append(chain, opts.authn.Middleware)
+	}
+	// 인증된 클라이언트에게만 리소스 경로를 알려줌
+	if len(opts.preload) > 0 {
+		chain = append(chain, middleware.EarlyHints(opts.preload))
+	}
+	// 조건부 요청은 압축 전의 응답으로 평가하므로 압축 미들웨어 안쪽에 위치
+	chain = append(chain,
+		middleware.Gzip(gzip.DefaultCompression),
+		middleware.Conditional(1<<20),
+		middleware.MaxBodySize(opts.maxBody),
+	)
+
 	srv := &http.Server{
 		Addr:              addr,
-		Handler:           mux,
+		Handler:           middleware.Chain(router, chain...),
 		IdleTimeout:       time.Minute,
 		ReadHeaderTimeout: 30 * time.Second,
 	}
+	if opts.clientCAs != nil {
+		// 클라이언트 인증서는 선택 사항이며, 제시한 경우에만 검증함
+		// 인증서가 없는 클라이언트는 다른 방식으로 인증할 수 있음
+		srv.TLSConfig = &tls.Config{
+			ClientCAs:  opts.clientCAs,
+			ClientAuth: tls.VerifyClientCertIfGiven,
+		}
+	}
 
 	go func() {
 		c := make(chan os.Signal, 1)
@@ -101,9 +265,8 @@ func run(addr, files, cert, pkey string) error {
 		}
 	}()
 
-	log.Printf("Serving files in %q over %s\n", files, srv.Addr)
+	log.Printf("Listening on %s\n", srv.Addr)
 
-	var err error
 	if cert != "" && pkey != "" {
 		log.Println("TLS enabled")
 		// 인증서의 경로와 개인키 경로를 모두 전달해주면, 서버는 ListenAndServeTLS 메서드를 사용해 TLS를 지원함
@@ -121,7 +121,8 @@ func TestSimpleHTTPServerMethods(t *testing.T) {
 		{http.MethodGet, nil, http.StatusOK, "Hello, friend!"},
 		{http.MethodPost, bytes.NewBufferString("<world>"), http.StatusOK,
 			"Hello, &lt;world&gt;!"},
-		{http.MethodHead, nil, http.StatusMethodNotAllowed, ""},
+		// GET 핸들러가 HEAD 요청을 처리하고, response body는 버려짐
+		{http.MethodHead, nil, http.StatusOK, ""},
 	}
 
 	client := new(http.Client)
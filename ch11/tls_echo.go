@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"time"
+
+	"github.com/awoodbeck/gnp/ch03/server"
 )
 
 func NewTLSServer(ctx context.Context, address string,
@@ -48,15 +50,9 @@ func (s *Server) ListenAndServeTLS(certFn, keyFn string) error {
 		return fmt.Errorf("binding to tcp %s: %w", s.addr, err)
 	}
 
-	if s.ctx != nil {
-
-		// ctx가 닫히면, 리스너를 종료하는 고루틴을 시작
-		go func() {
-			<-s.ctx.Done()
-			_ = l.Close()
-		}()
-	}
 	// 서버의 ServeTLS 메서드로 생성한 리스너 객체와 인증서 경로, 개인키 경로를 전달함
+	// ctx가 닫히면 ServeTLS 메서드가 server.Server를 통해 리스너를 닫으므로, 여기서 따로 닫지 않음
+	// 리스너를 닫는 경로가 둘이면, 어느 쪽이 먼저 닫느냐에 따라 반환하는 에러가 달라짐
 	return s.ServeTLS(l, certFn, keyFn)
 }
 
@@ -77,6 +73,7 @@ func (s Server) ServeTLS(l net.Listener, certFn, keyFn string) error {
 		// 매개변수로 입력받은 인증서와 개인키의 경로를 사용해 파일시스템으로부터 해당 파일을 읽어 tls.Certificate 객체를 생성
 		cert, err := tls.LoadX509KeyPair(certFn, keyFn)
 		if err != nil {
+			_ = l.Close()
 			return fmt.Errorf("loading key pair: %v", err)
 		}
 
@@ -90,34 +87,23 @@ func (s Server) ServeTLS(l net.Listener, certFn, keyFn string) error {
 		close(s.ready)
 	}
 
-	for {
-		conn, err := tlsListener.Accept()
-		if err != nil {
-			return fmt.Errorf("accept: %v", err)
-		}
-
+	// Accept 루프, 연결별 고루틴, 유휴 타임아웃 처리는 server 패키지에 맡김
+	// 읽기, 쓰기가 일어날 때마다 idle.Conn이 maxIdle만큼 데드라인을 연장해 줌
+	srv := &server.Server{
+		Handler:     server.Echo(),
+		IdleTimeout: s.maxIdle,
+	}
+	if s.ctx != nil {
 		go func() {
-			defer func() { _ = conn.Close() }()
-
-			for {
-				if s.maxIdle > 0 {
-					err := conn.SetDeadline(time.Now().Add(s.maxIdle))
-					if err != nil {
-						return
-					}
-				}
-
-				buf := make([]byte, 1024)
-				n, err := conn.Read(buf)
-				if err != nil {
-					return
-				}
-
-				_, err = conn.Write(buf[:n])
-				if err != nil {
-					return
-				}
-			}
+			<-s.ctx.Done()
+			_ = srv.Close()
 		}()
 	}
+
+	err := srv.Serve(tlsListener)
+	if err == server.ErrServerClosed {
+		return nil
+	}
+
+	return fmt.Errorf("accept: %v", err)
 }
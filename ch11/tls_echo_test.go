@@ -7,7 +7,6 @@ import (
 	"crypto/x509"
 	"io"
 	"os"
-	"strings"
 	"testing"
 	"time"
 )
@@ -23,9 +22,9 @@ func TestEchoServerTLS(t *testing.T) {
 
 	go func() {
 		// 백그라운드에서 서버 시작
+		// ctx가 닫혀 서버가 종료되면 nil을 반환
 		err := server.ListenAndServeTLS("cert.pem", "key.pem")
-		if err != nil && !strings.Contains(err.Error(),
-			"use of closed network connection") {
+		if err != nil {
 			t.Error(err)
 			return
 		}
@@ -95,3 +94,26 @@ func TestEchoServerTLS(t *testing.T) {
 	cancel()
 	<-done
 }
+
+// 종료 경로가 하나뿐이므로, ctx를 닫으면 항상 nil을 반환
+func TestEchoServerTLSShutdown(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		server := NewTLSServer(ctx, "127.0.0.1:0", time.Second, nil)
+
+		errc := make(chan error, 1)
+		go func() { errc <- server.ListenAndServeTLS("cert.pem", "key.pem") }()
+
+		server.Ready()
+		cancel()
+
+		select {
+		case err := <-errc:
+			if err != nil {
+				t.Fatalf("%d: expected nil; actual %v", i, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d: timed out waiting for shutdown", i)
+		}
+	}
+}